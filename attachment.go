@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backlog "github.com/moutend/go-backlog"
+)
+
+// uploadAttachments uploads the files via the space attachment API.
+// The returned attachments are not linked to anything until their IDs are passed as attachmentId[].
+func uploadAttachments(paths []string) (attachments []backlog.Attachment, err error) {
+	for _, path := range paths {
+		attachment, err := client.UploadAttachment(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot upload %s: %v", filepath.Base(path), err)
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+// resolveAttachmentPaths resolves the relative paths of files written in a frontmatter against dir.
+func resolveAttachmentPaths(paths []string, dir string) (resolved []string) {
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		resolved = append(resolved, path)
+	}
+
+	return resolved
+}
+
+// addAttachmentIds adds the IDs of attachments to the query as attachmentId[].
+func addAttachmentIds(query url.Values, attachments []backlog.Attachment) {
+	for _, attachment := range attachments {
+		query.Add("attachmentId[]", fmt.Sprint(attachment.Id))
+	}
+}
+
+// addAttachmentPlaceholders adds attachmentId[] for each file in dry run, where the real IDs are unknown until the files are uploaded.
+// The files are checked to exist, so that a dry run fails for the same missing files as the real run.
+func addAttachmentPlaceholders(query url.Values, paths []string) error {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+
+		query.Add("attachmentId[]", fmt.Sprintf("<id of %s>", filepath.Base(path)))
+	}
+
+	return nil
+}
+
+// safeFileName strips the directories from the name given by the server, so that the file never escapes the target directory.
+func safeFileName(name string) (string, error) {
+	base := filepath.Base(strings.Replace(name, "\\", "/", -1))
+
+	switch base {
+	case "", ".", "..", "/":
+		return "", fmt.Errorf("invalid file name: %q", name)
+	}
+
+	return base, nil
+}
+
+// downloadFile creates the file at the path and writes the downloaded content into it.
+// When the path is an existing directory, the file is created in it with the given name.
+func downloadFile(path, name string, download func(w io.Writer) error) (string, error) {
+	name, err := safeFileName(name)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		path = name
+	} else if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, name)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return path, err
+	}
+	if err := download(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return path, err
+	}
+
+	return path, file.Close()
+}
+
+// formatSize formats the size in bytes into human readable form such as 1.5 KB.
+func formatSize(size uint64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	value := float64(size)
+	i := 0
+
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d %s", size, units[i])
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeFileName(t *testing.T) {
+	for input, expected := range map[string]string{
+		"foo.png":         "foo.png",
+		"../../.bashrc":   ".bashrc",
+		"/etc/passwd":     "passwd",
+		`..\..\evil.exe`:  "evil.exe",
+		"dir/report.xlsx": "report.xlsx",
+	} {
+		name, err := safeFileName(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, name, input)
+	}
+	for _, input := range []string{"", ".", "..", "/", "foo/.."} {
+		_, err := safeFileName(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -18,4 +18,10 @@ const (
 	StatusesCache
 	WikisCache
 	WikiCache
+	UsersCache
+	StarsCache
+	WikiHistoriesCache
+	MilestonesCache
+	ResolutionsCache
+	CategoriesCache
 )
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+var categoryCommand = &cobra.Command{
+	Use: "category",
+	RunE: func(c *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var categoryListCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := fetchCategories(project.Id); err != nil {
+			return err
+		}
+
+		categories, err := readCategories(project.Id)
+		if err != nil {
+			return err
+		}
+		for _, category := range categories {
+			fmt.Printf("- %s\n", category.Name)
+		}
+
+		return nil
+	},
+}
+
+var categoryCreateCommand = &cobra.Command{
+	Use:     "create",
+	Aliases: []string{"c"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("name", args[1])
+
+		category, err := client.AddCategory(fmt.Sprint(project.Id), query)
+		if err != nil {
+			return err
+		}
+
+		// Refresh the cache on the next lookup, so that the new category can be used in the frontmatter right away.
+		if err := clearCategories(project.Id); err != nil {
+			return err
+		}
+
+		fmt.Println("created", category.Name)
+
+		return nil
+	},
+}
+
+var categoryDeleteYesFlag bool
+var categoryDeleteCommand = &cobra.Command{
+	Use:     "delete",
+	Aliases: []string{"d"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := fetchCategories(project.Id); err != nil {
+			return err
+		}
+
+		categories, err := readCategories(project.Id)
+		if err != nil {
+			return err
+		}
+
+		category, err := findCategoryByName(categories, args[1])
+		if err != nil {
+			return err
+		}
+		if !categoryDeleteYesFlag && !confirm(fmt.Sprintf("delete category %s from %s?", category.Name, project.ProjectKey)) {
+			return nil
+		}
+
+		category, err = client.DeleteCategory(fmt.Sprint(project.Id), fmt.Sprint(category.Id))
+		if err != nil {
+			return err
+		}
+		if err := clearCategories(project.Id); err != nil {
+			return err
+		}
+
+		fmt.Println("deleted", category.Name)
+
+		return nil
+	},
+}
+
+func fetchCategories(projectId uint64) error {
+	q := url.Values{}
+	q.Add("projectId", fmt.Sprint(projectId))
+
+	if time.Now().Sub(lastExecuted(CategoriesCache, q)) < 24*time.Hour {
+		return nil
+	}
+
+	categories, err := client.GetCategories(fmt.Sprint(projectId))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(categories)
+	if err != nil {
+		return err
+	}
+
+	base, err := cachePath(CategoriesCache)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(base, 0755)
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", projectId))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	if err := setLastExecuted(CategoriesCache, q); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readCategories(projectId uint64) (categories []backlog.Category, err error) {
+	base, err := cachePath(CategoriesCache)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", projectId))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// clearCategories expires the cached categories of the project, so that the next fetchCategories calls the API.
+func clearCategories(projectId uint64) error {
+	q := url.Values{}
+	q.Add("projectId", fmt.Sprint(projectId))
+
+	path, err := lastExecutedPath(CategoriesCache, q)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func findCategoryByName(categories []backlog.Category, name string) (category backlog.Category, err error) {
+	for _, c := range categories {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	return category, fmt.Errorf("category not found: %s", name)
+}
+
+func init() {
+	categoryDeleteCommand.Flags().BoolVarP(&categoryDeleteYesFlag, "yes", "y", false, "delete without confirmation")
+
+	categoryCommand.AddCommand(categoryListCommand)
+	categoryCommand.AddCommand(categoryCreateCommand)
+	categoryCommand.AddCommand(categoryDeleteCommand)
+
+	rootCommand.AddCommand(categoryCommand)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// renderFieldName returns the human readable label of the field in the change log.
+func renderFieldName(field string) string {
+	if label := localize("field." + field); label != "field."+field {
+		return label
+	}
+
+	return field
+}
+
+// renderChangeValue formats the value of the field in the change log.
+func renderChangeValue(field, value string) string {
+	if value == "" {
+		return localize("change.none")
+	}
+
+	switch field {
+	case "startDate", "limitDate":
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.Format(localize("format.date"))
+			}
+		}
+	}
+
+	return value
+}
+
+// renderChange formats the change of the field such as "Status: Open -> Closed".
+func renderChange(field, originalValue, newValue string) string {
+	label := renderFieldName(field)
+
+	switch field {
+	case "attachment":
+		if originalValue == "" {
+			return fmt.Sprintf("%s: "+localize("change.attached"), label, newValue)
+		}
+		if newValue == "" {
+			return fmt.Sprintf("%s: "+localize("change.detached"), label, originalValue)
+		}
+	}
+
+	return fmt.Sprintf("%s: %s -> %s", label, renderChangeValue(field, originalValue), renderChangeValue(field, newValue))
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderChange(t *testing.T) {
+	os.Setenv("BACKLOG_LANG", "en")
+	defer os.Unsetenv("BACKLOG_LANG")
+
+	assert.Equal(t, "Status: Open -> Closed", renderChange("status", "Open", "Closed"))
+	assert.Equal(t, "Due Date: (none) -> Mar 4, 2019", renderChange("limitDate", "", "2019-03-04T00:00:00Z"))
+	assert.Equal(t, "Attachment: attached foo.png", renderChange("attachment", "", "foo.png"))
+	assert.Equal(t, "customField: a -> b", renderChange("customField", "a", "b"))
+
+	os.Setenv("BACKLOG_LANG", "ja_JP.UTF-8")
+
+	assert.Equal(t, "状態: 未対応 -> 完了", renderChange("status", "未対応", "完了"))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -8,7 +9,9 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	backlog "github.com/moutend/go-backlog"
 	"github.com/spf13/cobra"
@@ -34,6 +37,120 @@ var commentCommand = &cobra.Command{
 	},
 }
 
+// commentTarget is the issue or the pull request which comments belong to.
+type commentTarget struct {
+	Issue      backlog.Issue
+	Project    backlog.Project
+	Repository backlog.Repository
+	Number     string
+}
+
+func (t commentTarget) IsPullRequest() bool {
+	return t.Number != ""
+}
+
+// commentTargetArgs is the unresolved form of commentTarget as written on the command line.
+type commentTargetArgs struct {
+	Issue          string
+	ProjectKey     string
+	RepositoryName string
+	Number         string
+}
+
+// parseCommentTargetArgs splits ISSUE, pr/NUMBER or PROJECT REPO NUMBER at the head of args from the rest of args.
+// ISSUE is either the issue key or the numeric issue ID.
+// With pr/NUMBER, the project and the repository are left empty to be inferred from the git remote.
+func parseCommentTargetArgs(args []string) (t commentTargetArgs, rest []string, err error) {
+	if len(args) == 0 {
+		return t, nil, fmt.Errorf("specify issue or pull-request")
+	}
+	if findIssueKey(args[0]) == args[0] {
+		t.Issue = args[0]
+		return t, args[1:], nil
+	}
+	if _, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+		t.Issue = args[0]
+		return t, args[1:], nil
+	}
+	if strings.HasPrefix(args[0], "pr/") {
+		t.Number = strings.TrimPrefix(args[0], "pr/")
+
+		if _, err := strconv.ParseUint(t.Number, 10, 64); err != nil {
+			return t, nil, fmt.Errorf("invalid pull-request number: %s", args[0])
+		}
+
+		return t, args[1:], nil
+	}
+	if len(args) < 3 {
+		return t, nil, fmt.Errorf("specify issue or pull-request")
+	}
+
+	t.ProjectKey = args[0]
+	t.RepositoryName = args[1]
+	t.Number = args[2]
+
+	return t, args[3:], nil
+}
+
+// readCommentTarget resolves ISSUE, pr/NUMBER or PROJECT REPO NUMBER at the head of args and returns the rest of args.
+func readCommentTarget(args []string) (target commentTarget, rest []string, err error) {
+	t, rest, err := parseCommentTargetArgs(args)
+	if err != nil {
+		return target, nil, err
+	}
+	if t.Issue != "" {
+		if err := fetchIssue(t.Issue); err != nil {
+			return target, nil, err
+		}
+
+		target.Issue, err = readIssue(t.Issue)
+		if err != nil {
+			return target, nil, err
+		}
+
+		return target, rest, nil
+	}
+	if t.ProjectKey == "" {
+		t.ProjectKey, t.RepositoryName, err = resolveProjectAndRepository(nil)
+		if err != nil {
+			return target, nil, err
+		}
+	}
+
+	projectKey := t.ProjectKey
+	repositoryName := t.RepositoryName
+
+	if err := fetchProjectByProjectKey(projectKey); err != nil {
+		return target, nil, err
+	}
+
+	target.Project, err = readProjectByProjectKey(projectKey)
+	if err != nil {
+		return target, nil, err
+	}
+
+	if err := fetchRepository(target.Project.Id, repositoryName); err != nil {
+		return target, nil, err
+	}
+
+	target.Repository, err = readRepository(target.Project.Id, repositoryName)
+	if err != nil {
+		return target, nil, err
+	}
+
+	target.Number = t.Number
+
+	return target, rest, nil
+}
+
+var (
+	commentShowSinceFlag       string
+	commentShowLimitFlag       int
+	commentShowOrderFlag       string
+	commentShowAuthorFlag      string
+	commentShowOnlyChangesFlag bool
+	commentShowNoChangesFlag   bool
+)
 var commentShowCommand = &cobra.Command{
 	Use:     "show",
 	Aliases: []string{"s"},
@@ -41,86 +158,336 @@ var commentShowCommand = &cobra.Command{
 		if len(args) == 0 {
 			return nil
 		}
+		if commentShowOnlyChangesFlag && commentShowNoChangesFlag {
+			return fmt.Errorf("--only-changes and --no-changes cannot be used together")
+		}
 
-		var comments []backlog.Comment
+		target, _, err := readCommentTarget(args)
+		if err != nil {
+			return err
+		}
 
-		switch len(args) {
-		case 1: // issue
-			issueKey := args[0]
+		var comments []backlog.Comment
 
-			if err := fetchIssue(issueKey); err != nil {
+		if target.IsPullRequest() {
+			if err := fetchPullRequestComments(target.Project.Id, target.Repository.Id, target.Number); err != nil {
 				return err
 			}
 
-			issue, err := readIssue(issueKey)
+			comments, err = readPullRequestComments(target.Project.Id, target.Repository.Id, target.Number)
 			if err != nil {
 				return err
 			}
+		} else {
+			if err := fetchIssueComments(target.Issue.Id); err != nil {
+				return err
+			}
 
-			if err := fetchIssueComments(issue.Id); err != nil {
+			comments, err = readIssueComments(target.Issue.Id)
+			if err != nil {
 				return err
 			}
+		}
+
+		var since time.Time
 
-			comments, err = readIssueComments(issue.Id)
+		if commentShowSinceFlag != "" {
+			since, err = parseDate(commentShowSinceFlag)
 			if err != nil {
 				return err
 			}
-		case 3: // pull-request
-			projectKey := args[0]
-			repositoryName := args[1]
-			number := args[2]
+		}
+
+		switch commentShowOrderFlag {
+		case "asc":
+			sort.Slice(comments, func(i, j int) bool {
+				return comments[i].Created.Time().Before(comments[j].Created.Time())
+			})
+		case "desc":
+			sort.Slice(comments, func(i, j int) bool {
+				return comments[i].Created.Time().After(comments[j].Created.Time())
+			})
+		default:
+			return fmt.Errorf("unknown order: %s", commentShowOrderFlag)
+		}
+
+		filtered := []backlog.Comment{}
+
+		for _, comment := range comments {
+			if comment.Created.Time().Before(since) {
+				continue
+			}
+			if commentShowAuthorFlag != "" && commentShowAuthorFlag != comment.CreatedUser.Name && commentShowAuthorFlag != comment.CreatedUser.UserId {
+				continue
+			}
+			if commentShowOnlyChangesFlag && len(comment.ChangeLog) == 0 {
+				continue
+			}
+			if commentShowNoChangesFlag && len(comment.ChangeLog) > 0 {
+				continue
+			}
+
+			filtered = append(filtered, comment)
+		}
+		if commentShowLimitFlag > 0 && len(filtered) > commentShowLimitFlag {
+			filtered = filtered[:commentShowLimitFlag]
+		}
+
+		color := isTerminal()
+		width := terminalWidth()
+		output := &bytes.Buffer{}
 
-			if err := fetchProjectByProjectKey(projectKey); err != nil {
+		fmt.Fprintf(output, localize("comment.found")+"\n", len(filtered))
+		for _, comment := range filtered {
+			author := comment.CreatedUser.Name
+			if color {
+				author = colorize(author)
+			}
+
+			fmt.Fprintf(output, "\n#%d %s %s", comment.Id, author, comment.Created.Time().Format("2006-01-02 15:04"))
+			if comment.Updated.Time().After(comment.Created.Time()) {
+				fmt.Fprintf(output, " (updated %s)", comment.Updated.Time().Format("2006-01-02 15:04"))
+			}
+			if len(comment.Stars) > 0 {
+				fmt.Fprintf(output, " ★%d", len(comment.Stars))
+			}
+			fmt.Fprintln(output)
+
+			if len(comment.ChangeLog) > 0 {
+				fmt.Fprintf(output, localize("comment.changed")+"\n", comment.CreatedUser.Name)
+				for _, change := range comment.ChangeLog {
+					fmt.Fprintln(output, "  ", renderChange(change.Field, change.OriginalValue, change.NewValue))
+				}
+			}
+			if comment.Content != "" {
+				fmt.Fprintln(output, wrapText(comment.Content, width))
+			}
+			if len(comment.Notifications) > 0 {
+				names := []string{}
+
+				for _, notification := range comment.Notifications {
+					names = append(names, notification.User.Name)
+				}
+
+				fmt.Fprintf(output, "   "+localize("comment.notified")+"\n", strings.Join(names, ", "))
+			}
+		}
+
+		return printWithPager(output)
+	},
+}
+
+var (
+	commentAddNotifyFlag     []string
+	commentAddAttachmentFlag []string
+)
+var commentAddCommand = &cobra.Command{
+	Use:     "add",
+	Aliases: []string{"a"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return nil
+		}
+
+		target, rest, err := readCommentTarget(args)
+		if err != nil {
+			return err
+		}
+
+		var path string
+
+		if len(rest) > 0 {
+			path = rest[0]
+		}
+
+		content, err := readText(path, "backlog-comment-*.md")
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(content) == "" {
+			return fmt.Errorf("aborted due to empty comment")
+		}
+
+		query := url.Values{}
+		query.Add("content", content)
+
+		if len(commentAddNotifyFlag) > 0 {
+			projectId := target.Issue.ProjectId
+
+			if target.IsPullRequest() {
+				projectId = target.Project.Id
+			}
+			if err := fetchUsers(projectId); err != nil {
 				return err
 			}
 
-			project, err := readProjectByProjectKey(projectKey)
+			users, err := readUsers(projectId)
 			if err != nil {
 				return err
 			}
+			for _, name := range commentAddNotifyFlag {
+				user, err := findUserByName(users, name)
+				if err != nil {
+					return err
+				}
 
-			if err := fetchRepository(project.Id, repositoryName); err != nil {
-				return err
+				query.Add("notifiedUserId[]", fmt.Sprint(user.Id))
 			}
+		}
 
-			repository, err := readRepository(project.Id, repositoryName)
+		attachments, err := uploadAttachments(commentAddAttachmentFlag)
+		if err != nil {
+			return err
+		}
+
+		addAttachmentIds(query, attachments)
+
+		if target.IsPullRequest() {
+			comment, err := client.AddPullRequestComment(fmt.Sprint(target.Project.Id), fmt.Sprint(target.Repository.Id), target.Number, query)
 			if err != nil {
 				return err
 			}
-
-			if err := fetchPullRequestComments(project.Id, repository.Id, number); err != nil {
+			if err := writePullRequestComment(target.Project.Id, target.Repository.Id, target.Number, comment); err != nil {
 				return err
 			}
 
-			comments, err = readPullRequestComments(project.Id, repository.Id, number)
+			fmt.Println("added comment", comment.Id)
+		} else {
+			comment, err := client.AddIssueComment(target.Issue.IssueKey, query)
 			if err != nil {
 				return err
 			}
+			if err := writeIssueComment(target.Issue.Id, comment); err != nil {
+				return err
+			}
 
-		default:
-			return fmt.Errorf("specify issue or pull-request")
+			fmt.Println("added comment", comment.Id)
 		}
 
-		sort.Slice(comments, func(i, j int) bool {
-			return comments[i].Created.Time().Before(comments[j].Created.Time())
-		})
-		fmt.Printf("found %d comment(s)\n", len(comments))
-		for i, _ := range comments {
-			comment := comments[len(comments)-i-1]
-			if len(comment.ChangeLog) > 0 {
-				fmt.Println(comment.CreatedUser.Name, "が課題の内容を変更しました。")
-				fmt.Println(comment.Content)
-				for _, change := range comment.ChangeLog {
-					fmt.Println("  ", change.Field, change.OriginalValue, "->", change.NewValue)
-				}
-			} else {
-				fmt.Println(comment.CreatedUser.Name, comment.Content)
-			}
+		return nil
+	},
+}
+
+var commentEditForceFlag bool
+var commentEditCommand = &cobra.Command{
+	Use:     "edit",
+	Aliases: []string{"e"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		issue, comment, err := readOwnIssueComment(args[0], args[1], commentEditForceFlag)
+		if err != nil {
+			return err
+		}
+
+		path, err := editTempFile("backlog-comment-*.md", comment.Content)
+		defer os.Remove(path)
+		if err != nil {
+			return err
 		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		content := string(data)
+
+		if strings.TrimSpace(content) == "" {
+			return fmt.Errorf("aborted due to empty comment")
+		}
+		if content == comment.Content {
+			fmt.Println("no changes")
+			return nil
+		}
+
+		query := url.Values{}
+		query.Add("content", content)
+
+		comment, err = client.UpdateIssueComment(issue.IssueKey, comment.Id, query)
+		if err != nil {
+			return err
+		}
+		if err := writeIssueComment(issue.Id, comment); err != nil {
+			return err
+		}
+
+		fmt.Println("updated comment", comment.Id)
+
 		return nil
 	},
 }
 
+var commentDeleteForceFlag bool
+var commentDeleteCommand = &cobra.Command{
+	Use:     "delete",
+	Aliases: []string{"d"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		issue, comment, err := readOwnIssueComment(args[0], args[1], commentDeleteForceFlag)
+		if err != nil {
+			return err
+		}
+
+		if _, err := client.DeleteIssueComment(issue.IssueKey, comment.Id); err != nil {
+			return err
+		}
+		if err := removeIssueComment(comment.Id); err != nil {
+			return err
+		}
+
+		fmt.Println("deleted comment", comment.Id)
+
+		return nil
+	},
+}
+
+// readOwnIssueComment reads the comment of the issue from the cache.
+// It fails when the comment was written by someone else, unless force is true.
+func readOwnIssueComment(issueKey, commentId string, force bool) (issue backlog.Issue, comment backlog.Comment, err error) {
+	id, err := strconv.ParseUint(commentId, 10, 64)
+	if err != nil {
+		return issue, comment, err
+	}
+
+	if err := fetchIssue(issueKey); err != nil {
+		return issue, comment, err
+	}
+
+	issue, err = readIssue(issueKey)
+	if err != nil {
+		return issue, comment, err
+	}
+
+	if err := fetchIssueComments(issue.Id); err != nil {
+		return issue, comment, err
+	}
+
+	comment, err = readIssueComment(issue.Id, id)
+	if err != nil {
+		return issue, comment, err
+	}
+
+	if err := fetchMyself(); err != nil {
+		return issue, comment, err
+	}
+
+	myself, err := readMyself()
+	if err != nil {
+		return issue, comment, err
+	}
+	if comment.CreatedUser.Id != myself.Id && !force {
+		return issue, comment, fmt.Errorf("comment %d was written by %s (use --force to proceed)", comment.Id, comment.CreatedUser.Name)
+	}
+
+	return issue, comment, nil
+}
+
 func fetchIssueComments(issueId uint64) error {
 	query := url.Values{}
 	query.Add("count", "100")
@@ -129,25 +496,60 @@ func fetchIssueComments(issueId uint64) error {
 		return err
 	}
 
-	base, err := cachePath(IssueCommentsCache)
-	if err != nil {
-		return err
+	for _, comment := range comments {
+		if err := writeIssueComment(issueId, comment); err != nil {
+			return err
+		}
 	}
 
-	os.MkdirAll(base, 0755)
+	return nil
+}
 
-	for _, comment := range comments {
-		c := IssueComment{
-			IssueId: issueId,
-			Comment: comment,
+// fetchIssueCommentsSince fetches the comments of the issue from the newest one back to the first one posted before since.
+func fetchIssueCommentsSince(issueId uint64, since time.Time) error {
+	var maxId uint64
+
+	for {
+		query := url.Values{}
+		query.Add("count", "100")
+		query.Add("order", "desc")
+
+		if maxId != 0 {
+			query.Add("maxId", fmt.Sprint(maxId))
 		}
-		data, err := json.Marshal(c)
+
+		comments, err := client.GetIssueComments(issueId, query)
 		if err != nil {
 			return err
 		}
 
-		path := filepath.Join(base, fmt.Sprintf("%d.json", comment.Id))
-		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		for _, comment := range comments {
+			if err := writeIssueComment(issueId, comment); err != nil {
+				return err
+			}
+		}
+		if len(comments) < 100 {
+			return nil
+		}
+
+		oldest := comments[len(comments)-1]
+
+		if oldest.Created.Time().Before(since) || oldest.Id == maxId {
+			return nil
+		}
+
+		maxId = oldest.Id
+	}
+}
+
+func fetchPullRequestComments(projectId, repositoryId uint64, number string) error {
+	comments, err := client.GetPullRequestComments(fmt.Sprint(projectId), fmt.Sprint(repositoryId), number, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		if err := writePullRequestComment(projectId, repositoryId, number, comment); err != nil {
 			return err
 		}
 	}
@@ -155,12 +557,32 @@ func fetchIssueComments(issueId uint64) error {
 	return nil
 }
 
-func fetchPullRequestComments(projectId, repositoryId uint64, number string) error {
-	comments, err := client.GetPullRequestComments(fmt.Sprint(projectId), fmt.Sprint(repositoryId), number, nil)
+func writeIssueComment(issueId uint64, comment backlog.Comment) error {
+	base, err := cachePath(IssueCommentsCache)
 	if err != nil {
 		return err
 	}
 
+	os.MkdirAll(base, 0755)
+
+	c := IssueComment{
+		IssueId: issueId,
+		Comment: comment,
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", comment.Id))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func writePullRequestComment(projectId, repositoryId uint64, number string, comment backlog.Comment) error {
 	base, err := cachePath(PullRequestCommentsCache)
 	if err != nil {
 		return err
@@ -168,33 +590,43 @@ func fetchPullRequestComments(projectId, repositoryId uint64, number string) err
 
 	os.MkdirAll(base, 0755)
 
-	for _, comment := range comments {
-		c := PullRequestComment{
-			ProjectId:    projectId,
-			RepositoryId: repositoryId,
-			Number:       number,
-			Comment:      comment,
-		}
-		data, err := json.Marshal(c)
-		if err != nil {
-			return err
-		}
+	c := PullRequestComment{
+		ProjectId:    projectId,
+		RepositoryId: repositoryId,
+		Number:       number,
+		Comment:      comment,
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
 
-		path := filepath.Join(base, fmt.Sprintf("%d.json", comment.Id))
-		if err := ioutil.WriteFile(path, data, 0644); err != nil {
-			return err
-		}
+	path := filepath.Join(base, fmt.Sprintf("%d.json", comment.Id))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func readIssueComments(issueId uint64) (comments []backlog.Comment, err error) {
+	grouped, err := readIssueCommentsByIssue()
+	if err != nil {
+		return nil, err
+	}
+
+	return grouped[issueId], nil
+}
+
+// readIssueCommentsByIssue reads every cached issue comment at once, grouped by the issue ID.
+func readIssueCommentsByIssue() (map[uint64][]backlog.Comment, error) {
 	base, err := cachePath(IssueCommentsCache)
 	if err != nil {
 		return nil, err
 	}
 
+	comments := map[uint64][]backlog.Comment{}
+
 	err = filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
 		if !strings.HasSuffix(path, ".json") {
 			return nil
@@ -210,9 +642,8 @@ func readIssueComments(issueId uint64) (comments []backlog.Comment, err error) {
 		if err := json.Unmarshal(data, &ic); err != nil {
 			return err
 		}
-		if ic.IssueId == issueId {
-			comments = append(comments, ic.Comment)
-		}
+
+		comments[ic.IssueId] = append(comments[ic.IssueId], ic.Comment)
 
 		return nil
 	})
@@ -223,6 +654,44 @@ func readIssueComments(issueId uint64) (comments []backlog.Comment, err error) {
 	return comments, nil
 }
 
+func readIssueComment(issueId, commentId uint64) (comment backlog.Comment, err error) {
+	base, err := cachePath(IssueCommentsCache)
+	if err != nil {
+		return comment, err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", commentId))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return comment, err
+	}
+
+	var ic IssueComment
+
+	if err := json.Unmarshal(data, &ic); err != nil {
+		return comment, err
+	}
+	if ic.IssueId != issueId {
+		return comment, fmt.Errorf("comment %d does not belong to the issue", commentId)
+	}
+
+	return ic.Comment, nil
+}
+
+func removeIssueComment(commentId uint64) error {
+	base, err := cachePath(IssueCommentsCache)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", commentId))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
 func readPullRequestComments(projectId, repositoryId uint64, number string) (comments []backlog.Comment, err error) {
 	base, err := cachePath(PullRequestCommentsCache)
 	if err != nil {
@@ -258,7 +727,21 @@ func readPullRequestComments(projectId, repositoryId uint64, number string) (com
 }
 
 func init() {
+	commentShowCommand.Flags().StringVarP(&commentShowSinceFlag, "since", "", "", "pick comments created since the date (YYYY-MM-DD)")
+	commentShowCommand.Flags().IntVarP(&commentShowLimitFlag, "limit", "l", 0, "maximum number of comments")
+	commentShowCommand.Flags().StringVarP(&commentShowOrderFlag, "order", "o", "desc", "order of comments (asc or desc)")
+	commentShowCommand.Flags().StringVarP(&commentShowAuthorFlag, "author", "a", "", "pick comments written by the user")
+	commentShowCommand.Flags().BoolVarP(&commentShowOnlyChangesFlag, "only-changes", "", false, "pick comments which change the issue")
+	commentShowCommand.Flags().BoolVarP(&commentShowNoChangesFlag, "no-changes", "", false, "pick comments which do not change the issue")
+	commentAddCommand.Flags().StringSliceVarP(&commentAddNotifyFlag, "notify", "n", nil, "notify the users (comma separated)")
+	commentAddCommand.Flags().StringSliceVarP(&commentAddAttachmentFlag, "attachment", "a", nil, "attach the files (comma separated)")
+	commentEditCommand.Flags().BoolVarP(&commentEditForceFlag, "force", "f", false, "edit the comment even if it was written by someone else")
+	commentDeleteCommand.Flags().BoolVarP(&commentDeleteForceFlag, "force", "f", false, "delete the comment even if it was written by someone else")
+
 	commentCommand.AddCommand(commentShowCommand)
+	commentCommand.AddCommand(commentAddCommand)
+	commentCommand.AddCommand(commentEditCommand)
+	commentCommand.AddCommand(commentDeleteCommand)
 
 	rootCommand.AddCommand(commentCommand)
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommentTargetArgs(t *testing.T) {
+	target, rest, err := parseCommentTargetArgs([]string{"PROJ-123", "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, commentTargetArgs{Issue: "PROJ-123"}, target)
+	assert.Equal(t, []string{"hello"}, rest)
+
+	target, rest, err = parseCommentTargetArgs([]string{"12345", "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, commentTargetArgs{Issue: "12345"}, target)
+	assert.Equal(t, []string{"hello"}, rest)
+
+	target, rest, err = parseCommentTargetArgs([]string{"pr/12", "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, commentTargetArgs{Number: "12"}, target)
+	assert.Equal(t, []string{"hello"}, rest)
+
+	target, rest, err = parseCommentTargetArgs([]string{"PROJ", "repo", "12", "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, commentTargetArgs{ProjectKey: "PROJ", RepositoryName: "repo", Number: "12"}, target)
+	assert.Equal(t, []string{"hello"}, rest)
+
+	_, _, err = parseCommentTargetArgs([]string{"pr/abc"})
+	assert.Error(t, err)
+
+	_, _, err = parseCommentTargetArgs([]string{"PROJ", "repo"})
+	assert.Error(t, err)
+
+	_, _, err = parseCommentTargetArgs(nil)
+	assert.Error(t, err)
+}
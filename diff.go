@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type diffLine struct {
+	Kind byte // ' ', '-' or '+'
+	Text string
+}
+
+// diffLines computes the line based difference between a and b with the longest common subsequence.
+func diffLines(a, b []string) (lines []diffLine) {
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// unifiedDiff returns the difference between from and to in the unified format with 3 lines of context.
+// It returns an empty string when there is no difference.
+func unifiedDiff(fromName, toName, from, to string) string {
+	const context = 3
+
+	lines := diffLines(splitLines(from), splitLines(to))
+	changed := []int{}
+
+	for i, line := range lines {
+		if line.Kind != ' ' {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "--- %s\n", fromName)
+	fmt.Fprintf(&sb, "+++ %s\n", toName)
+
+	for k := 0; k < len(changed); {
+		start := changed[k] - context
+		if start < 0 {
+			start = 0
+		}
+
+		end := changed[k] + context + 1
+
+		for k++; k < len(changed) && changed[k]-context <= end; k++ {
+			end = changed[k] + context + 1
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		fromLine, toLine := 1, 1
+
+		for _, line := range lines[:start] {
+			if line.Kind != '+' {
+				fromLine++
+			}
+			if line.Kind != '-' {
+				toLine++
+			}
+		}
+
+		fromCount, toCount := 0, 0
+
+		for _, line := range lines[start:end] {
+			if line.Kind != '+' {
+				fromCount++
+			}
+			if line.Kind != '-' {
+				toCount++
+			}
+		}
+		if fromCount == 0 {
+			fromLine--
+		}
+		if toCount == 0 {
+			toLine--
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+
+		for _, line := range lines[start:end] {
+			fmt.Fprintf(&sb, "%c%s\n", line.Kind, line.Text)
+		}
+	}
+
+	return sb.String()
+}
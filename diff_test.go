@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Equal(t, "", unifiedDiff("a", "b", "foo\nbar\n", "foo\nbar\n"))
+
+	expected := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n foo\n-bar\n+baz\n qux\n"
+	assert.Equal(t, expected, unifiedDiff("a", "b", "foo\nbar\nqux\n", "foo\nbaz\nqux\n"))
+
+	expected = "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+foo\n"
+	assert.Equal(t, expected, unifiedDiff("a", "b", "", "foo\n"))
+}
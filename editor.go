@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// editTempFile writes the text into a temporary file and opens it with $EDITOR.
+// The caller is responsible for removing the returned file.
+func editTempFile(pattern, text string) (path string, err error) {
+	file, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", err
+	}
+
+	path = file.Name()
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return path, err
+	}
+	if err := file.Close(); err != nil {
+		return path, err
+	}
+
+	// A blank $EDITOR is treated as unset.
+	ss := strings.Fields(os.Getenv("EDITOR"))
+	if len(ss) == 0 {
+		ss = []string{"vi"}
+	}
+
+	cmd := exec.Command(ss[0], append(ss[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return path, err
+	}
+
+	return path, nil
+}
+
+// readText reads the text from the file, from stdin when the path is "-", or from $EDITOR when the path is empty.
+func readText(path, pattern string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch path {
+	case "-":
+		data, err = ioutil.ReadAll(os.Stdin)
+	case "":
+		path, err = editTempFile(pattern, "")
+		defer os.Remove(path)
+		if err != nil {
+			return "", err
+		}
+
+		data, err = ioutil.ReadFile(path)
+	default:
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
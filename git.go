@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+type gitRemote struct {
+	ProjectKey     string
+	RepositoryName string
+}
+
+func runGit(args ...string) (string, error) {
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseGitRemoteURL extracts the project key and repository name from the URL of a Backlog git repository.
+// Both SSH (xxx@xxx.git.backlog.jp:/PROJ/repo.git) and HTTPS (https://xxx.backlog.jp/git/PROJ/repo.git) forms are supported.
+func parseGitRemoteURL(rawurl string) (remote gitRemote, err error) {
+	var path string
+
+	if strings.Contains(rawurl, "://") {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return remote, err
+		}
+
+		path = u.Path
+	} else {
+		i := strings.Index(rawurl, ":")
+		if i < 0 {
+			return remote, fmt.Errorf("invalid remote URL: %s", rawurl)
+		}
+
+		path = rawurl[i+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	ss := strings.Split(path, "/")
+
+	if len(ss) < 2 || ss[len(ss)-2] == "" || ss[len(ss)-1] == "" {
+		return remote, fmt.Errorf("invalid remote URL: %s", rawurl)
+	}
+
+	remote.ProjectKey = ss[len(ss)-2]
+	remote.RepositoryName = ss[len(ss)-1]
+
+	return remote, nil
+}
+
+func readGitRemote() (remote gitRemote, err error) {
+	rawurl, err := runGit("config", "--get", "remote.origin.url")
+	if err != nil {
+		return remote, fmt.Errorf("cannot read remote.origin.url: %v", err)
+	}
+
+	return parseGitRemoteURL(rawurl)
+}
+
+func readGitCurrentBranch() (string, error) {
+	return runGit("rev-parse", "--abbrev-ref", "HEAD")
+}
+
+func readGitDefaultBranch() string {
+	branch, err := runGit("symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil || branch == "" {
+		return "master"
+	}
+
+	return strings.TrimPrefix(branch, "origin/")
+}
+
+// resolveProjectAndRepository returns the project key and repository name given as the first two arguments.
+// When they are omitted, the ones of the current git remote are used instead.
+func resolveProjectAndRepository(args []string) (projectKey, repositoryName string, err error) {
+	if len(args) >= 2 {
+		return args[0], args[1], nil
+	}
+
+	remote, err := readGitRemote()
+	if err != nil {
+		return "", "", err
+	}
+
+	return remote.ProjectKey, remote.RepositoryName, nil
+}
+
+var issueKeyPattern = regexp.MustCompile(`[A-Z][A-Z0-9_]*-[0-9]+`)
+
+// findIssueKey returns the issue key embedded in the branch name such as feature/PROJ-123-foo.
+func findIssueKey(branch string) string {
+	return issueKeyPattern.FindString(branch)
+}
+
+// readGitCommitMessages returns the subject and body of commits between base and HEAD in chronological order.
+func readGitCommitMessages(base string) (subjects, bodies []string, err error) {
+	output, err := runGit("log", "--reverse", "--format=%s%x1f%b%x1e", base+"..HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		ss := strings.SplitN(record, "\x1f", 2)
+		subjects = append(subjects, strings.TrimSpace(ss[0]))
+
+		if len(ss) == 2 {
+			bodies = append(bodies, strings.TrimSpace(ss[1]))
+		} else {
+			bodies = append(bodies, "")
+		}
+	}
+
+	return subjects, bodies, nil
+}
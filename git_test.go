@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitRemoteURL(t *testing.T) {
+	r1, err := parseGitRemoteURL("space@space.git.backlog.jp:/PROJ/repo.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "PROJ", r1.ProjectKey)
+	assert.Equal(t, "repo", r1.RepositoryName)
+
+	r2, err := parseGitRemoteURL("https://space.backlog.jp/git/PROJ/repo.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "PROJ", r2.ProjectKey)
+	assert.Equal(t, "repo", r2.RepositoryName)
+
+	r3, err := parseGitRemoteURL("ssh://space@space.git.backlog.jp/PROJ/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "PROJ", r3.ProjectKey)
+	assert.Equal(t, "repo", r3.RepositoryName)
+
+	_, err = parseGitRemoteURL("repo.git")
+	assert.Error(t, err)
+}
+
+func TestFindIssueKey(t *testing.T) {
+	assert.Equal(t, "PROJ-123", findIssueKey("feature/PROJ-123-foo"))
+	assert.Equal(t, "PROJ_2-1", findIssueKey("PROJ_2-1"))
+	assert.Equal(t, "", findIssueKey("feature/foo"))
+}
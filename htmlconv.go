@@ -0,0 +1,410 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// wikiResolver rewrites the references in wiki content while converting it into HTML.
+type wikiResolver struct {
+	// Page returns the URL of the wiki page of the name.
+	Page func(name string) string
+	// Image returns the URL of the image, which may be the name of attachment.
+	Image func(src string) string
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+func escapeAttribute(s string) string {
+	return strings.Replace(escapeHTML(s), `"`, "&quot;", -1)
+}
+
+// safeURL returns the URL unchanged when it is relative or its scheme is http, https or mailto, and "#" otherwise.
+// This keeps javascript: and other schemes in the wiki from being exported as live links.
+func safeURL(s string) string {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexAny(s, ":/?#")
+	if i < 0 || s[i] != ':' {
+		return s
+	}
+
+	switch strings.ToLower(s[:i]) {
+	case "http", "https", "mailto":
+		return s
+	}
+
+	return "#"
+}
+
+// inlineRule replaces the matches of pattern by the result of render, which receives the submatches of the unescaped text.
+type inlineRule struct {
+	pattern *regexp.Regexp
+	render  func(ss []string) string
+}
+
+// renderInline applies the rules in order and escapes the rest of text.
+// The HTML generated by a rule is never processed by the following rules.
+func renderInline(s string, rules []inlineRule) string {
+	if len(rules) == 0 {
+		return escapeHTML(s)
+	}
+
+	rule := rules[0]
+	result := ""
+	last := 0
+
+	for _, m := range rule.pattern.FindAllStringSubmatchIndex(s, -1) {
+		ss := []string{}
+
+		for i := 0; i < len(m); i += 2 {
+			if m[i] < 0 {
+				ss = append(ss, "")
+			} else {
+				ss = append(ss, s[m[i]:m[i+1]])
+			}
+		}
+
+		result += renderInline(s[last:m[0]], rules[1:])
+		result += rule.render(ss)
+		last = m[1]
+	}
+
+	return result + renderInline(s[last:], rules[1:])
+}
+
+func markdownInlineRules(r wikiResolver) (rules []inlineRule) {
+	rules = []inlineRule{
+		{regexp.MustCompile("`([^`]+)`"), func(ss []string) string {
+			return "<code>" + escapeHTML(ss[1]) + "</code>"
+		}},
+		{regexp.MustCompile(`\[\[([^\]]+)\]\]`), func(ss []string) string {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, escapeAttribute(r.Page(ss[1])), escapeHTML(ss[1]))
+		}},
+		{regexp.MustCompile(`!\[([^\]]*)\][\[(]([^\])]+)[\])]`), func(ss []string) string {
+			return fmt.Sprintf(`<img src="%s" alt="%s">`, escapeAttribute(safeURL(r.Image(ss[2]))), escapeAttribute(ss[1]))
+		}},
+		// The target may contain balanced parentheses, as in https://en.wikipedia.org/wiki/Go_(programming_language).
+		{regexp.MustCompile(`\[([^\]]+)\]\(((?:[^()\s]|\([^()\s]*\))+)\)`), func(ss []string) string {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, escapeAttribute(safeURL(ss[2])), renderInline(ss[1], rules[4:]))
+		}},
+		{regexp.MustCompile(`\*\*(.+?)\*\*`), func(ss []string) string {
+			return "<strong>" + renderInline(ss[1], rules[5:]) + "</strong>"
+		}},
+		{regexp.MustCompile(`\*(.+?)\*`), func(ss []string) string {
+			return "<em>" + renderInline(ss[1], rules[6:]) + "</em>"
+		}},
+		{regexp.MustCompile(`~~(.+?)~~`), func(ss []string) string {
+			return "<del>" + escapeHTML(ss[1]) + "</del>"
+		}},
+	}
+
+	return rules
+}
+
+func backlogInlineRules(r wikiResolver) (rules []inlineRule) {
+	rules = []inlineRule{
+		{regexp.MustCompile(`\[\[([^\]]+?)(?:[>:](https?://[^\]]+|[^\]]+))?\]\]`), func(ss []string) string {
+			if ss[2] == "" {
+				return fmt.Sprintf(`<a href="%s">%s</a>`, escapeAttribute(r.Page(ss[1])), escapeHTML(ss[1]))
+			}
+			if strings.Contains(ss[2], "://") {
+				return fmt.Sprintf(`<a href="%s">%s</a>`, escapeAttribute(safeURL(ss[2])), escapeHTML(ss[1]))
+			}
+
+			return fmt.Sprintf(`<a href="%s">%s</a>`, escapeAttribute(r.Page(ss[2])), escapeHTML(ss[1]))
+		}},
+		{regexp.MustCompile(`#(?:image|thumbnail)\(([^)]+)\)`), func(ss []string) string {
+			return fmt.Sprintf(`<img src="%s" alt="%s">`, escapeAttribute(safeURL(r.Image(ss[1]))), escapeAttribute(ss[1]))
+		}},
+		{regexp.MustCompile(`'''(.+?)'''`), func(ss []string) string {
+			return "<em>" + renderInline(ss[1], rules[3:]) + "</em>"
+		}},
+		{regexp.MustCompile(`''(.+?)''`), func(ss []string) string {
+			return "<strong>" + renderInline(ss[1], rules[4:]) + "</strong>"
+		}},
+		{regexp.MustCompile(`%%(.+?)%%`), func(ss []string) string {
+			return "<del>" + renderInline(ss[1], rules[5:]) + "</del>"
+		}},
+		{regexp.MustCompile(`&color\(([#\w]+)\)\s*\{(.+?)\}`), func(ss []string) string {
+			return fmt.Sprintf(`<span style="color: %s">%s</span>`, escapeAttribute(ss[1]), escapeHTML(ss[2]))
+		}},
+	}
+
+	return rules
+}
+
+// renderTable renders the rows separated by "|" as a table.
+// The rows marked as header are rendered with th.
+func renderTable(rows [][]string, header []bool, rules []inlineRule) string {
+	s := "<table>\n"
+
+	for i, row := range rows {
+		tag := "td"
+
+		if header[i] {
+			tag = "th"
+		}
+
+		s += "<tr>"
+
+		for _, cell := range row {
+			s += fmt.Sprintf("<%s>%s</%s>", tag, renderInline(strings.TrimSpace(cell), rules), tag)
+		}
+
+		s += "</tr>\n"
+	}
+
+	return s + "</table>\n"
+}
+
+// listRenderer renders nested lists from the items with their depth.
+type listRenderer struct {
+	tags []string
+	html string
+}
+
+func (l *listRenderer) add(depth int, tag, item string) {
+	for len(l.tags) > depth || (len(l.tags) == depth && depth > 0 && l.tags[depth-1] != tag) {
+		l.html += fmt.Sprintf("</li></%s>\n", l.tags[len(l.tags)-1])
+		l.tags = l.tags[:len(l.tags)-1]
+	}
+	if len(l.tags) == depth && depth > 0 {
+		l.html += "</li>\n"
+	}
+	for len(l.tags) < depth {
+		l.html += fmt.Sprintf("<%s>\n", tag)
+		l.tags = append(l.tags, tag)
+	}
+
+	l.html += "<li>" + item
+}
+
+func (l *listRenderer) close() string {
+	for len(l.tags) > 0 {
+		l.html += fmt.Sprintf("</li></%s>\n", l.tags[len(l.tags)-1])
+		l.tags = l.tags[:len(l.tags)-1]
+	}
+
+	s := l.html
+	l.html = ""
+
+	return s
+}
+
+var (
+	markdownFencePattern   = regexp.MustCompile("^\\s*```")
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	markdownRulePattern    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownListPattern    = regexp.MustCompile(`^(\s*)([-*+]|\d+\.)\s+(.*)$`)
+	markdownTableSeparator = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+)
+
+// markdownToHTML converts Backlog flavored Markdown into HTML.
+// Like Backlog, a line break in a paragraph is rendered as br.
+func markdownToHTML(text string, r wikiResolver) string {
+	rules := markdownInlineRules(r)
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	list := &listRenderer{}
+	paragraph := []string{}
+	s := ""
+
+	flush := func() {
+		s += list.close()
+
+		if len(paragraph) > 0 {
+			s += "<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n"
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case markdownFencePattern.MatchString(line):
+			flush()
+
+			code := []string{}
+
+			for i++; i < len(lines) && !markdownFencePattern.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+
+			s += "<pre><code>" + escapeHTML(strings.Join(code, "\n")) + "</code></pre>\n"
+		case strings.TrimSpace(line) == "":
+			flush()
+		case markdownHeadingPattern.MatchString(line):
+			flush()
+
+			ss := markdownHeadingPattern.FindStringSubmatch(line)
+			s += fmt.Sprintf("<h%d>%s</h%d>\n", len(ss[1]), renderInline(ss[2], rules), len(ss[1]))
+		case markdownRulePattern.MatchString(line):
+			flush()
+
+			s += "<hr>\n"
+		case strings.HasPrefix(line, ">"):
+			flush()
+
+			quote := []string{}
+
+			for ; i < len(lines) && strings.HasPrefix(lines[i], ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(lines[i], ">"), " "))
+			}
+
+			i--
+			s += "<blockquote>\n" + markdownToHTML(strings.Join(quote, "\n"), r) + "</blockquote>\n"
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			flush()
+
+			rows := [][]string{}
+			header := []bool{}
+
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				if markdownTableSeparator.MatchString(strings.TrimSpace(lines[i])) {
+					for j := range header {
+						header[j] = true
+					}
+					continue
+				}
+
+				rows = append(rows, splitTableRow(lines[i]))
+				header = append(header, false)
+			}
+
+			i--
+			s += renderTable(rows, header, rules)
+		case markdownListPattern.MatchString(line):
+			if len(paragraph) > 0 {
+				flush()
+			}
+
+			ss := markdownListPattern.FindStringSubmatch(line)
+			tag := "ul"
+
+			if strings.HasSuffix(ss[2], ".") {
+				tag = "ol"
+			}
+
+			list.add(len(strings.Replace(ss[1], "\t", "    ", -1))/2+1, tag, renderInline(ss[3], rules))
+		default:
+			s += list.close()
+			paragraph = append(paragraph, renderInline(line, rules))
+		}
+	}
+
+	flush()
+
+	return s
+}
+
+var (
+	backlogCodePattern    = regexp.MustCompile(`^\s*\{code(:[^}]*)?\}\s*$`)
+	backlogHeadingPattern = regexp.MustCompile(`^(\*{1,6})\s*(.*)$`)
+	backlogListPattern    = regexp.MustCompile(`^([-+]+)\s*(.*)$`)
+)
+
+// backlogToHTML converts Backlog notation into HTML.
+func backlogToHTML(text string, r wikiResolver) string {
+	rules := backlogInlineRules(r)
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	list := &listRenderer{}
+	paragraph := []string{}
+	s := ""
+
+	flush := func() {
+		s += list.close()
+
+		if len(paragraph) > 0 {
+			s += "<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n"
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case backlogCodePattern.MatchString(line):
+			flush()
+
+			code := []string{}
+
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "{/code}"; i++ {
+				code = append(code, lines[i])
+			}
+
+			s += "<pre><code>" + escapeHTML(strings.Join(code, "\n")) + "</code></pre>\n"
+		case strings.TrimSpace(line) == "{quote}":
+			flush()
+
+			quote := []string{}
+
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "{/quote}"; i++ {
+				quote = append(quote, lines[i])
+			}
+
+			s += "<blockquote>\n" + backlogToHTML(strings.Join(quote, "\n"), r) + "</blockquote>\n"
+		case strings.TrimSpace(line) == "":
+			flush()
+		case strings.TrimSpace(line) == "----":
+			flush()
+
+			s += "<hr>\n"
+		case backlogHeadingPattern.MatchString(line):
+			flush()
+
+			ss := backlogHeadingPattern.FindStringSubmatch(line)
+			s += fmt.Sprintf("<h%d>%s</h%d>\n", len(ss[1]), renderInline(ss[2], rules), len(ss[1]))
+		case strings.HasPrefix(line, "|"):
+			flush()
+
+			rows := [][]string{}
+			header := []bool{}
+
+			for ; i < len(lines) && strings.HasPrefix(lines[i], "|"); i++ {
+				row := strings.TrimSpace(lines[i])
+				isHeader := strings.HasSuffix(row, "|h")
+
+				rows = append(rows, splitTableRow(strings.TrimSuffix(row, "h")))
+				header = append(header, isHeader)
+			}
+
+			i--
+			s += renderTable(rows, header, rules)
+		case backlogListPattern.MatchString(line):
+			if len(paragraph) > 0 {
+				flush()
+			}
+
+			ss := backlogListPattern.FindStringSubmatch(line)
+			tag := "ul"
+
+			if ss[1][0] == '+' {
+				tag = "ol"
+			}
+
+			list.add(len(ss[1]), tag, renderInline(ss[2], rules))
+		default:
+			s += list.close()
+			paragraph = append(paragraph, renderInline(line, rules))
+		}
+	}
+
+	flush()
+
+	return s
+}
+
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+
+	return strings.Split(row, "|")
+}
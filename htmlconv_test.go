@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testWikiResolver = wikiResolver{
+	Page:  func(name string) string { return name + ".html" },
+	Image: func(src string) string { return "attachments/" + src },
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	assert.Equal(t, "<h1>Title</h1>\n", markdownToHTML("# Title", testWikiResolver))
+	assert.Equal(t, "<p><strong>a</strong> <code>*b*</code> <a href=\"Home.html\">Home</a></p>\n", markdownToHTML("**a** `*b*` [[Home]]", testWikiResolver))
+	assert.Equal(t, "<ul>\n<li>a<ul>\n<li>b</li></ul>\n</li></ul>\n", markdownToHTML("- a\n  - b", testWikiResolver))
+	assert.Equal(t, "<pre><code>&lt;x&gt;</code></pre>\n", markdownToHTML("```\n<x>\n```", testWikiResolver))
+	assert.Equal(t, "<p><a href=\"#\">x</a></p>\n", markdownToHTML("[x](javascript:alert(1))", testWikiResolver))
+	assert.Equal(t, "<p><a href=\"https://en.wikipedia.org/wiki/Go_(language)\">Go</a></p>\n", markdownToHTML("[Go](https://en.wikipedia.org/wiki/Go_(language))", testWikiResolver))
+	assert.Equal(t, "<p><a href=\"mailto:a@example.com\">mail</a> <a href=\"../a.html?x=1&amp;y=2\">rel</a></p>\n", markdownToHTML("[mail](mailto:a@example.com) [rel](../a.html?x=1&y=2)", testWikiResolver))
+}
+
+func TestSafeURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/a:b", safeURL("https://example.com/a:b"))
+	assert.Equal(t, "HTTP://example.com", safeURL("HTTP://example.com"))
+	assert.Equal(t, "a/b:c.html", safeURL("a/b:c.html"))
+	assert.Equal(t, "#", safeURL("javascript:alert(1)"))
+	assert.Equal(t, "#", safeURL(" JavaScript:alert(1)"))
+	assert.Equal(t, "#", safeURL("data:text/html,x"))
+}
+
+func TestBacklogToHTML(t *testing.T) {
+	assert.Equal(t, "<h2>Title</h2>\n", backlogToHTML("** Title", testWikiResolver))
+	assert.Equal(t, "<p><strong>a</strong> <a href=\"https://example.com\">b</a></p>\n", backlogToHTML("''a'' [[b>https://example.com]]", testWikiResolver))
+	assert.Equal(t, "<p><img src=\"attachments/a.png\" alt=\"a.png\"></p>\n", backlogToHTML("#image(a.png)", testWikiResolver))
+	assert.Equal(t, "<p><a href=\"#\">b</a></p>\n", backlogToHTML("[[b>javascript://%0aalert(1)]]", testWikiResolver))
+}
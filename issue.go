@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,25 +25,112 @@ var issueCommand = &cobra.Command{
 	},
 }
 
-var (
-	issueListMyselfFlag bool
-)
+// issueFilter holds the conditions to pick issues.
+// It is shared by issue list and the commands which operate on the same set of issues.
+type issueFilter struct {
+	Myself    bool
+	Milestone string
+
+	assignee       backlog.User
+	milestoneFound bool
+}
+
+// resolve looks up the users and others referenced by the conditions.
+func (f *issueFilter) resolve() error {
+	if f.Myself {
+		if err := fetchMyself(); err != nil {
+			return err
+		}
+
+		myself, err := readMyself()
+		if err != nil {
+			return err
+		}
+
+		f.assignee = myself
+	}
+
+	return nil
+}
+
+// empty reports whether no conditions are set, in which case every issue matches.
+func (f *issueFilter) empty() bool {
+	return !f.Myself && f.Milestone == ""
+}
+
+func (f *issueFilter) query() url.Values {
+	query := url.Values{}
+	query.Add("sort", "updated")
+	query.Add("order", "desc")
+
+	if f.assignee.Id != 0 {
+		query.Add("assigneeId[]", fmt.Sprint(f.assignee.Id))
+	}
+
+	return query
+}
+
+func (f *issueFilter) match(issue backlog.Issue) bool {
+	if f.assignee.Id != 0 && f.assignee.Id != issue.Assignee.Id {
+		return false
+	}
+
+	return true
+}
+
+// selectIssues fetches the issues of the project and returns the ones matching the filter, sorted by updated date.
+// Milestones are looked up by name in each project, and the projects without the milestone have no matching issues.
+// The filter records whether any project has the milestone, so that the caller can report a misspelled name.
+func selectIssues(project backlog.Project, filter *issueFilter) (issues []backlog.Issue, err error) {
+	query := filter.query()
+
+	var milestone backlog.Version
+
+	if filter.Milestone != "" {
+		var ok bool
+
+		milestone, ok, err = lookupMilestone(project.Id, filter.Milestone)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+
+		filter.milestoneFound = true
+		query.Add("milestoneId[]", fmt.Sprint(milestone.Id))
+	}
+	if err := fetchIssues(project.Id, query); err != nil {
+		return nil, err
+	}
+
+	cached, err := readIssues(project.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range cached {
+		if milestone.Id != 0 && !hasMilestone(issue, milestone.Id) {
+			continue
+		}
+		if filter.match(issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Updated.Time().After(issues[j].Updated.Time())
+	})
+
+	return issues, nil
+}
+
+var issueListFilter issueFilter
 var issueListCommand = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l"},
 	RunE: func(c *cobra.Command, args []string) error {
-		var assignee backlog.User
-
-		if issueListMyselfFlag {
-			err := fetchMyself()
-			if err != nil {
-				return err
-			}
-
-			assignee, err = readMyself()
-			if err != nil {
-				return err
-			}
+		if err := issueListFilter.resolve(); err != nil {
+			return err
 		}
 
 		if err := fetchProjects(); err != nil {
@@ -53,35 +142,18 @@ var issueListCommand = &cobra.Command{
 			return err
 		}
 
-		query := url.Values{}
-		query.Add("sort", "updated")
-		query.Add("order", "desc")
-
-		if assignee.Id != 0 {
-			query.Add("assigneeId[]", fmt.Sprint(assignee.Id))
-		}
-
 		for _, project := range projects {
-			if err := fetchIssues(project.Id, query); err != nil {
-				return err
-			}
-
-			issues, err := readIssues(project.Id)
+			issues, err := selectIssues(project, &issueListFilter)
 			if err != nil {
 				return err
 			}
-
-			sort.Slice(issues, func(i, j int) bool {
-				return issues[i].Updated.Time().After(issues[j].Updated.Time())
-			})
+			if issueListFilter.Milestone != "" && len(issues) == 0 {
+				continue
+			}
 
 			fmt.Printf("- [%s] %s\n", project.ProjectKey, project.Name)
 
 			for _, issue := range issues {
-				if assignee.Id != 0 && assignee.Id != issue.Assignee.Id {
-					continue
-				}
-
 				fmt.Printf(
 					"  - [%s] (%s) %s (updated at %s by %s)\n",
 					issue.IssueKey,
@@ -92,6 +164,9 @@ var issueListCommand = &cobra.Command{
 				)
 			}
 		}
+		if issueListFilter.Milestone != "" && !issueListFilter.milestoneFound {
+			return fmt.Errorf("no such milestone: %s", issueListFilter.Milestone)
+		}
 
 		return nil
 	},
@@ -116,60 +191,98 @@ var issueShowCommand = &cobra.Command{
 			return err
 		}
 
-		if err := fetchProjectById(issue.ProjectId); err != nil {
+		text, err := renderIssueMarkdown(issue)
+		if err != nil {
 			return err
 		}
 
-		project, err := readProjectById(issue.ProjectId)
-		if err != nil {
-			return err
+		fmt.Print(text)
+
+		return nil
+	},
+}
+
+// quoteFrontmatter quotes the string as a YAML double-quoted scalar.
+// Bare values such as "fix: crash", "[WIP] foo" or "yes" would fail to parse or change their types.
+func quoteFrontmatter(s string) string {
+	return strconv.Quote(s)
+}
+
+// renderIssueMarkdown formats the issue as markdown with frontmatter, which can be edited and passed to issue update.
+func renderIssueMarkdown(issue backlog.Issue) (string, error) {
+	if err := fetchProjectById(issue.ProjectId); err != nil {
+		return "", err
+	}
+
+	project, err := readProjectById(issue.ProjectId)
+	if err != nil {
+		return "", err
+	}
+
+	var parentIssue backlog.Issue
+
+	if issue.ParentIssueId != 0 {
+		if err := fetchIssue(fmt.Sprint(issue.ParentIssueId)); err != nil {
+			return "", err
 		}
 
-		var parentIssue backlog.Issue
+		parentIssue, err = readIssue(fmt.Sprint(issue.ParentIssueId))
+		if err != nil {
+			return "", err
+		}
+	}
 
-		if issue.ParentIssueId != 0 {
-			if err := fetchIssue(fmt.Sprint(issue.ParentIssueId)); err != nil {
-				return err
-			}
+	w := &bytes.Buffer{}
 
-			parentIssue, err = readIssue(fmt.Sprint(issue.ParentIssueId))
-			if err != nil {
-				return err
-			}
+	date := func(t time.Time) string {
+		if t.Equal(time.Time{}) {
+			return ""
 		}
 
-		{
-			fmt.Println("---")
-			fmt.Println("summary:", issue.Summary)
-			fmt.Println("project:", project.ProjectKey)
-			if issue.ParentIssueId != 0 {
-				fmt.Println("parent:", parentIssue.IssueKey)
-			}
-			fmt.Println("type:", issue.IssueType.Name)
-			fmt.Println("status:", issue.Status.Name)
-			fmt.Println("priority:", issue.Priority.Name)
-			fmt.Println("assignee:", issue.Assignee.Name)
-			fmt.Println("created:", issue.CreatedUser.Name)
-			if issue.StartDate.Time().Equal(time.Time{}) {
-				fmt.Println("start: ")
-			} else {
-				fmt.Println("start:", issue.StartDate.Time().Format("2006-01-02"))
-			}
-			if issue.DueDate.Time().Equal(time.Time{}) {
-				fmt.Println("due: ")
-			} else {
-				fmt.Println("due:", issue.DueDate.Time().Format("2006-01-02"))
-			}
-			fmt.Println("estimated:", issue.EstimatedHours)
-			fmt.Println("actual:", issue.ActualHours)
-			fmt.Printf("url: https://%s.backlog.jp/view/%s\n", space, issue.IssueKey)
-			fmt.Println("---")
-			fmt.Printf("%s", issue.Description)
+		return t.Format("2006-01-02")
+	}
+
+	fmt.Fprintln(w, "---")
+	fmt.Fprintln(w, "summary:", quoteFrontmatter(issue.Summary))
+	fmt.Fprintln(w, "project:", quoteFrontmatter(project.ProjectKey))
+	if issue.ParentIssueId != 0 {
+		fmt.Fprintln(w, "parent:", quoteFrontmatter(parentIssue.IssueKey))
+	}
+	fmt.Fprintln(w, "type:", quoteFrontmatter(issue.IssueType.Name))
+	fmt.Fprintln(w, "status:", quoteFrontmatter(issue.Status.Name))
+	fmt.Fprintln(w, "priority:", quoteFrontmatter(issue.Priority.Name))
+	fmt.Fprintln(w, "assignee:", quoteFrontmatter(issue.Assignee.Name))
+	if len(issue.Category) > 0 {
+		fmt.Fprintln(w, "category:")
+		for _, category := range issue.Category {
+			fmt.Fprintf(w, "  - %s\n", quoteFrontmatter(category.Name))
 		}
-		return nil
-	},
+	}
+	fmt.Fprintln(w, "created:", quoteFrontmatter(issue.CreatedUser.Name))
+	fmt.Fprintln(w, "start:", quoteFrontmatter(date(issue.StartDate.Time())))
+	fmt.Fprintln(w, "due:", quoteFrontmatter(date(issue.DueDate.Time())))
+	fmt.Fprintln(w, "estimated:", quoteFrontmatter(formatOptionalHours(issue.EstimatedHours)))
+	fmt.Fprintln(w, "actual:", quoteFrontmatter(formatOptionalHours(issue.ActualHours)))
+	fmt.Fprintln(w, "stars:", len(issue.Stars))
+	if len(issue.Attachments) > 0 {
+		fmt.Fprintln(w, "attachments:")
+		for _, attachment := range issue.Attachments {
+			fmt.Fprintf(w, "  - %s # %s\n", quoteFrontmatter(attachment.Name), formatSize(attachment.Size))
+		}
+	}
+	fmt.Fprintln(w, "updated:", quoteFrontmatter(issue.Updated.Time().Format(time.RFC3339)))
+	fmt.Fprintln(w, "url:", quoteFrontmatter(fmt.Sprintf("https://%s.backlog.jp/view/%s", space, issue.IssueKey)))
+	fmt.Fprintln(w, "---")
+	fmt.Fprintf(w, "%s", issue.Description)
+
+	return w.String(), nil
 }
 
+var (
+	issueUpdateDryRunFlag bool
+	issueUpdateForceFlag  bool
+)
+
 var issueUpdateCommand = &cobra.Command{
 	Use:     "update",
 	Aliases: []string{"u"},
@@ -181,15 +294,45 @@ var issueUpdateCommand = &cobra.Command{
 		issueKey := args[0]
 		filePath := args[1]
 
-		query, err := parseIssueMarkdown(issueKey, filePath)
+		if !issueUpdateForceFlag {
+			if err := checkIssueConflict(issueKey, filePath); err != nil {
+				return err
+			}
+		}
+
+		query, attachmentPaths, err := parseIssueMarkdown(issueKey, filePath)
 		if err != nil {
 			return err
 		}
 
+		if issueUpdateDryRunFlag {
+			if err := addAttachmentPlaceholders(query, attachmentPaths); err != nil {
+				return err
+			}
+
+			fmt.Printf("PATCH /api/v2/issues/%s\n", issueKey)
+			printQuery(query)
+
+			return nil
+		}
+
+		attachments, err := uploadAttachments(attachmentPaths)
+		if err != nil {
+			return err
+		}
+
+		addAttachmentIds(query, attachments)
+
 		issue, err := client.UpdateIssue(issueKey, query)
 		if err != nil {
 			return err
 		}
+		if err := writeIssue(issue); err != nil {
+			return err
+		}
+		if err := touchIssueFile(filePath, issue.Updated.Time()); err != nil {
+			return err
+		}
 
 		fmt.Println("updated", issue.IssueKey)
 
@@ -210,7 +353,7 @@ var issueCreateCommand = &cobra.Command{
 
 		filePath := args[0]
 
-		v, err := parseIssueMarkdown("", filePath)
+		v, _, err := parseIssueMarkdown("", filePath)
 		if err != nil {
 			return err
 		}
@@ -265,6 +408,10 @@ func fetchIssue(issueKey string) error {
 		return err
 	}
 
+	return writeIssue(issue)
+}
+
+func writeIssue(issue backlog.Issue) error {
 	base, err := cachePath(IssuesCache)
 	if err != nil {
 		return err
@@ -351,7 +498,11 @@ func readIssue(issueKeyOrId string) (issue backlog.Issue, err error) {
 }
 
 func init() {
-	issueListCommand.Flags().BoolVarP(&issueListMyselfFlag, "myself", "m", false, "pick issues assigned to myself")
+	issueListCommand.Flags().BoolVarP(&issueListFilter.Myself, "myself", "m", false, "pick issues assigned to myself")
+	issueListCommand.Flags().StringVarP(&issueListFilter.Milestone, "milestone", "", "", "pick issues in the milestone")
+
+	issueUpdateCommand.Flags().BoolVarP(&issueUpdateDryRunFlag, "dry-run", "n", false, "print the fields to be sent without updating the issue")
+	issueUpdateCommand.Flags().BoolVarP(&issueUpdateForceFlag, "force", "f", false, "update the issue even if it was edited after the file was generated")
 
 	issueCommand.AddCommand(issueListCommand)
 	issueCommand.AddCommand(issueShowCommand)
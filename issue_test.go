@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ericaro/frontmatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteFrontmatter(t *testing.T) {
+	for _, summary := range []string{
+		"fix: crash on start",
+		"# not a comment",
+		"[WIP] foo",
+		"*emphasis*",
+		"yes",
+		"1.5",
+		"null",
+		`say "hi" \ bye`,
+		"タイトル: 日本語",
+		"",
+	} {
+		data := "---\nsummary: " + quoteFrontmatter(summary) + "\nstart: " + quoteFrontmatter("") + "\n---\nbody\n"
+
+		var fo issueFrontmatterOption
+
+		assert.NoError(t, frontmatter.Unmarshal([]byte(data), &fo), summary)
+		assert.Equal(t, summary, fo.Summary)
+		assert.Equal(t, "", fo.Start)
+	}
+}
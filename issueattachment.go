@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var issueAttachmentsCommand = &cobra.Command{
+	Use: "attachments",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		issueKey := args[0]
+
+		if err := fetchIssue(issueKey); err != nil {
+			return err
+		}
+
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("found %d attachment(s)\n", len(issue.Attachments))
+		for _, attachment := range issue.Attachments {
+			fmt.Printf("  - %s (%s) (%d)\n", attachment.Name, formatSize(attachment.Size), attachment.Id)
+		}
+
+		return nil
+	},
+}
+
+var (
+	issueDownloadAllFlag    bool
+	issueDownloadOutputFlag string
+)
+var issueDownloadCommand = &cobra.Command{
+	Use: "download",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+		if len(args) < 2 && !issueDownloadAllFlag {
+			return fmt.Errorf("specify attachment ID or --all")
+		}
+
+		issueKey := args[0]
+
+		if err := fetchIssue(issueKey); err != nil {
+			return err
+		}
+
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return err
+		}
+
+		var attachmentId uint64
+
+		if !issueDownloadAllFlag {
+			attachmentId, err = strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+		}
+		if issueDownloadOutputFlag != "" && issueDownloadAllFlag {
+			os.MkdirAll(issueDownloadOutputFlag, 0755)
+		}
+
+		found := false
+
+		for _, attachment := range issue.Attachments {
+			if !issueDownloadAllFlag && attachment.Id != attachmentId {
+				continue
+			}
+
+			id := attachment.Id
+			found = true
+
+			path, err := downloadFile(issueDownloadOutputFlag, attachment.Name, func(w io.Writer) error {
+				return client.DownloadIssueAttachment(issue.IssueKey, id, w)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("downloaded", path)
+		}
+		if !found && !issueDownloadAllFlag {
+			return fmt.Errorf("attachment %d not found", attachmentId)
+		}
+
+		return nil
+	},
+}
+
+var issueAttachCommand = &cobra.Command{
+	Use: "attach",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		issueKey := args[0]
+
+		attachments, err := uploadAttachments(args[1:])
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		addAttachmentIds(query, attachments)
+
+		issue, err := client.UpdateIssue(issueKey, query)
+		if err != nil {
+			return err
+		}
+		if err := writeIssue(issue); err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			fmt.Println("attached", attachment.Name)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	issueDownloadCommand.Flags().BoolVarP(&issueDownloadAllFlag, "all", "a", false, "download all attachments")
+	issueDownloadCommand.Flags().StringVarP(&issueDownloadOutputFlag, "output", "o", "", "path or directory to save the attachments")
+
+	issueCommand.AddCommand(issueAttachmentsCommand)
+	issueCommand.AddCommand(issueDownloadCommand)
+	issueCommand.AddCommand(issueAttachCommand)
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+// issueBulkUpdateConcurrency is the number of issues updated at the same time.
+const issueBulkUpdateConcurrency = 4
+
+var (
+	issueBulkUpdateFilter        issueFilter
+	issueBulkUpdateStatusFlag    string
+	issueBulkUpdateAssigneeFlag  string
+	issueBulkUpdateMilestoneFlag string
+	issueBulkUpdatePriorityFlag  string
+	issueBulkUpdateCommentFlag   string
+	issueBulkUpdateYesFlag       bool
+	issueBulkUpdateAllFlag       bool
+)
+var issueBulkUpdateCommand = &cobra.Command{
+	Use: "bulk-update",
+	RunE: func(c *cobra.Command, args []string) error {
+		issues, err := readBulkUpdateIssues(args)
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			return fmt.Errorf("no issues to update")
+		}
+
+		queries := make([]url.Values, len(issues))
+
+		for i, issue := range issues {
+			queries[i], err = buildBulkUpdateQuery(issue)
+			if err != nil {
+				return fmt.Errorf("%s: %v", issue.IssueKey, err)
+			}
+		}
+		if len(queries[0]) == 0 {
+			return fmt.Errorf("specify at least one change")
+		}
+
+		fmt.Printf("%-12s %-20s %-20s %s\n", "KEY", "STATUS", "ASSIGNEE", "SUMMARY")
+		for _, issue := range issues {
+			fmt.Printf("%-12s %-20s %-20s %s\n", issue.IssueKey, issue.Status.Name, issue.Assignee.Name, issue.Summary)
+		}
+		fmt.Println()
+		fmt.Println("changes:")
+		for _, change := range []struct{ name, value string }{
+			{"status", issueBulkUpdateStatusFlag},
+			{"assignee", issueBulkUpdateAssigneeFlag},
+			{"milestone", issueBulkUpdateMilestoneFlag},
+			{"priority", issueBulkUpdatePriorityFlag},
+			{"comment", issueBulkUpdateCommentFlag},
+		} {
+			if change.value != "" {
+				fmt.Printf("  %s: %s\n", change.name, change.value)
+			}
+		}
+
+		if !issueBulkUpdateYesFlag && !confirm(fmt.Sprintf("update %d issue(s)?", len(issues))) {
+			return fmt.Errorf("aborted")
+		}
+
+		errs := make([]error, len(issues))
+		semaphore := make(chan struct{}, issueBulkUpdateConcurrency)
+		wg := &sync.WaitGroup{}
+
+		for i := range issues {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				issue, err := client.UpdateIssue(issues[i].IssueKey, queries[i])
+				if err != nil {
+					errs[i] = err
+					return
+				}
+
+				errs[i] = writeIssue(issue)
+			}(i)
+		}
+
+		wg.Wait()
+
+		failed := 0
+
+		for i, issue := range issues {
+			if errs[i] != nil {
+				fmt.Printf("failed %s: %v\n", issue.IssueKey, errs[i])
+				failed++
+			} else {
+				fmt.Printf("updated %s\n", issue.IssueKey)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d issue(s) failed", failed, len(issues))
+		}
+
+		return nil
+	},
+}
+
+// readBulkUpdateIssues reads the issues given as arguments, from stdin when the argument is "-", or by the filter.
+// Selecting every cached issue requires --all, so that a mistyped command never updates the whole cache.
+func readBulkUpdateIssues(args []string) (issues []backlog.Issue, err error) {
+	if len(args) == 1 && args[0] == "-" {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		args = strings.Fields(string(data))
+
+		if len(args) == 0 {
+			return nil, nil
+		}
+	}
+	if len(args) > 0 {
+		for _, issueKey := range args {
+			if err := fetchIssue(issueKey); err != nil {
+				return nil, err
+			}
+
+			issue, err := readIssue(issueKey)
+			if err != nil {
+				return nil, err
+			}
+
+			issues = append(issues, issue)
+		}
+
+		return issues, nil
+	}
+	if issueBulkUpdateFilter.empty() && !issueBulkUpdateAllFlag {
+		return nil, fmt.Errorf("specify issue keys or a filter such as --myself and --milestone, or --all to update every issue")
+	}
+	if err := issueBulkUpdateFilter.resolve(); err != nil {
+		return nil, err
+	}
+
+	if err := fetchProjects(); err != nil {
+		return nil, err
+	}
+
+	projects, err := readProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		selected, err := selectIssues(project, &issueBulkUpdateFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, selected...)
+	}
+	if issueBulkUpdateFilter.Milestone != "" && !issueBulkUpdateFilter.milestoneFound {
+		return nil, fmt.Errorf("no such milestone: %s", issueBulkUpdateFilter.Milestone)
+	}
+
+	return issues, nil
+}
+
+// buildBulkUpdateQuery resolves the names given by the flags against the project of the issue.
+func buildBulkUpdateQuery(issue backlog.Issue) (url.Values, error) {
+	query := url.Values{}
+
+	if issueBulkUpdateStatusFlag != "" {
+		if err := fetchStatuses(); err != nil {
+			return nil, err
+		}
+
+		statuses, err := readStatuses()
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := findStatusByName(statuses, issueBulkUpdateStatusFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Add("statusId", fmt.Sprint(status.Id))
+	}
+	if issueBulkUpdateAssigneeFlag != "" {
+		if err := fetchUsers(issue.ProjectId); err != nil {
+			return nil, err
+		}
+
+		users, err := readUsers(issue.ProjectId)
+		if err != nil {
+			return nil, err
+		}
+
+		assignee, err := findUserByName(users, issueBulkUpdateAssigneeFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Add("assigneeId", fmt.Sprint(assignee.Id))
+	}
+	if issueBulkUpdateMilestoneFlag != "" {
+		milestone, err := readMilestoneByName(issue.ProjectId, issueBulkUpdateMilestoneFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Add("milestoneId[]", fmt.Sprint(milestone.Id))
+	}
+	if issueBulkUpdatePriorityFlag != "" {
+		if err := fetchPriorities(); err != nil {
+			return nil, err
+		}
+
+		priorities, err := readPriorities()
+		if err != nil {
+			return nil, err
+		}
+
+		priority, err := findPriorityByName(priorities, issueBulkUpdatePriorityFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Add("priorityId", fmt.Sprint(priority.Id))
+	}
+	if issueBulkUpdateCommentFlag != "" {
+		query.Add("comment", issueBulkUpdateCommentFlag)
+	}
+
+	return query, nil
+}
+
+func init() {
+	issueBulkUpdateCommand.Flags().BoolVarP(&issueBulkUpdateFilter.Myself, "myself", "m", false, "pick issues assigned to myself")
+	issueBulkUpdateCommand.Flags().StringVarP(&issueBulkUpdateFilter.Milestone, "milestone", "", "", "pick issues in the milestone")
+	issueBulkUpdateCommand.Flags().StringVarP(&issueBulkUpdateStatusFlag, "status", "s", "", "change the status")
+	issueBulkUpdateCommand.Flags().StringVarP(&issueBulkUpdateAssigneeFlag, "assignee", "a", "", "change the assignee")
+	issueBulkUpdateCommand.Flags().StringVarP(&issueBulkUpdateMilestoneFlag, "set-milestone", "", "", "change the milestone")
+	issueBulkUpdateCommand.Flags().StringVarP(&issueBulkUpdatePriorityFlag, "priority", "p", "", "change the priority")
+	issueBulkUpdateCommand.Flags().StringVarP(&issueBulkUpdateCommentFlag, "comment", "c", "", "add the comment")
+	issueBulkUpdateCommand.Flags().BoolVarP(&issueBulkUpdateYesFlag, "yes", "y", false, "update without confirmation")
+	issueBulkUpdateCommand.Flags().BoolVarP(&issueBulkUpdateAllFlag, "all", "", false, "update every issue when neither keys nor filters are given")
+
+	issueCommand.AddCommand(issueBulkUpdateCommand)
+}
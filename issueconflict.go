@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ericaro/frontmatter"
+)
+
+// checkIssueConflict compares the updated timestamp recorded in the file with the one of the issue on the server.
+// When the issue was edited after the file was generated, it prints the fields which differ and returns an error.
+// Files without the timestamp are not checked.
+func checkIssueConflict(issueKey, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var local issueFrontmatterOption
+
+	if err := frontmatter.Unmarshal(data, &local); err != nil {
+		return err
+	}
+	if local.Updated == "" {
+		return nil
+	}
+
+	updated, err := time.Parse(time.RFC3339, local.Updated)
+	if err != nil {
+		return fmt.Errorf("invalid updated: %s", local.Updated)
+	}
+
+	if err := fetchIssue(issueKey); err != nil {
+		return err
+	}
+
+	issue, err := readIssue(issueKey)
+	if err != nil {
+		return err
+	}
+	if !issue.Updated.Time().After(updated) {
+		return nil
+	}
+
+	text, err := renderIssueMarkdown(issue)
+	if err != nil {
+		return err
+	}
+
+	var remote issueFrontmatterOption
+
+	if err := frontmatter.Unmarshal([]byte(text), &remote); err != nil {
+		return err
+	}
+
+	keys, err := readFrontmatterKeys(data)
+	if err != nil {
+		return err
+	}
+
+	fields := []struct {
+		Name          string
+		Local, Remote string
+	}{
+		{"summary", local.Summary, remote.Summary},
+		{"project", local.Project, remote.Project},
+		{"parent", local.Parent, remote.Parent},
+		{"type", local.Type, remote.Type},
+		{"status", local.Status, remote.Status},
+		{"priority", local.Priority, remote.Priority},
+		{"assignee", local.Assignee, remote.Assignee},
+		{"category", strings.Join(local.Category, ", "), strings.Join(remote.Category, ", ")},
+		{"start", local.Start, remote.Start},
+		{"due", local.Due, remote.Due},
+		{"estimated", local.Estimated, remote.Estimated},
+		{"actual", local.Actual, remote.Actual},
+	}
+
+	fmt.Printf("%s was updated by %s at %s after the file was generated\n", issueKey, issue.UpdatedUser.Name, issue.Updated.Time().Format(time.RFC3339))
+
+	for _, field := range fields {
+		if !keys[field.Name] || field.Local == field.Remote {
+			continue
+		}
+
+		fmt.Printf("%s:\n", field.Name)
+		fmt.Printf("  - %s\n", field.Local)
+		fmt.Printf("  + %s\n", field.Remote)
+	}
+	if strings.TrimSpace(local.Content) != strings.TrimSpace(remote.Content) {
+		fmt.Print(unifiedDiff("local", "remote", local.Content, remote.Content))
+	}
+
+	return fmt.Errorf("%s has been edited remotely; run issue show again or pass --force to overwrite", issueKey)
+}
+
+// replaceFrontmatterUpdated rewrites the updated line in the frontmatter of the file.
+// The data is returned unchanged when the frontmatter has no updated line.
+func replaceFrontmatterUpdated(data []byte, updated time.Time) []byte {
+	lines := strings.SplitAfter(string(data), "\n")
+
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return data
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			break
+		}
+		if !strings.HasPrefix(lines[i], "updated:") {
+			continue
+		}
+
+		lines[i] = "updated: " + quoteFrontmatter(updated.Format(time.RFC3339)) + "\n"
+
+		return []byte(strings.Join(lines, ""))
+	}
+
+	return data
+}
+
+// touchIssueFile records the updated timestamp of the issue in the file, so that the next update of the same file is not reported as a conflict.
+func touchIssueFile(path string, updated time.Time) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, replaceFrontmatterUpdated(data, updated), info.Mode())
+}
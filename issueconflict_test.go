@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceFrontmatterUpdated(t *testing.T) {
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	data := "---\nsummary: \"foo\"\nupdated: \"2019-01-01T00:00:00Z\"\n---\nupdated: body\n"
+	expected := "---\nsummary: \"foo\"\nupdated: \"2020-01-02T03:04:05Z\"\n---\nupdated: body\n"
+	assert.Equal(t, expected, string(replaceFrontmatterUpdated([]byte(data), updated)))
+
+	// The body is not touched when the frontmatter has no updated line.
+	data = "---\nsummary: \"foo\"\n---\nupdated: body\n"
+	assert.Equal(t, data, string(replaceFrontmatterUpdated([]byte(data), updated)))
+
+	data = "updated: body\n"
+	assert.Equal(t, data, string(replaceFrontmatterUpdated([]byte(data), updated)))
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// issueCSVColumns are the columns of exported CSV, followed by the custom fields prefixed with "custom:".
+var issueCSVColumns = []string{
+	"key",
+	"summary",
+	"type",
+	"status",
+	"priority",
+	"assignee",
+	"parent",
+	"start",
+	"due",
+	"estimated",
+	"actual",
+	"milestone",
+	"created",
+	"created_by",
+	"updated",
+	"description",
+}
+
+var issueExportCSVFlag string
+var issueExportCommand = &cobra.Command{
+	Use: "export",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+		if issueExportCSVFlag == "" {
+			return fmt.Errorf("specify --csv")
+		}
+
+		projectKey := args[0]
+
+		if err := fetchProjectByProjectKey(projectKey); err != nil {
+			return err
+		}
+
+		project, err := readProjectByProjectKey(projectKey)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("sort", "updated")
+		query.Add("order", "desc")
+
+		if err := fetchIssues(project.Id, query); err != nil {
+			return err
+		}
+
+		issues, err := readIssues(project.Id)
+		if err != nil {
+			return err
+		}
+
+		sort.Slice(issues, func(i, j int) bool {
+			return issues[i].Id < issues[j].Id
+		})
+
+		keys := map[uint64]string{}
+		customFields := []string{}
+		seen := map[string]bool{}
+
+		for _, issue := range issues {
+			keys[issue.Id] = issue.IssueKey
+
+			for _, field := range issue.CustomFields {
+				if !seen[field.Name] {
+					seen[field.Name] = true
+					customFields = append(customFields, field.Name)
+				}
+			}
+		}
+
+		file, err := os.Create(issueExportCSVFlag)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		w := csv.NewWriter(file)
+		header := append([]string{}, issueCSVColumns...)
+
+		for _, name := range customFields {
+			header = append(header, "custom:"+name)
+		}
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			milestones := []string{}
+
+			for _, milestone := range issue.Milestone {
+				milestones = append(milestones, milestone.Name)
+			}
+
+			record := []string{
+				issue.IssueKey,
+				issue.Summary,
+				issue.IssueType.Name,
+				issue.Status.Name,
+				issue.Priority.Name,
+				issue.Assignee.Name,
+				keys[issue.ParentIssueId],
+				formatCSVDate(issue.StartDate.Time()),
+				formatCSVDate(issue.DueDate.Time()),
+				formatOptionalHours(issue.EstimatedHours),
+				formatOptionalHours(issue.ActualHours),
+				strings.Join(milestones, ","),
+				issue.Created.Time().Format(time.RFC3339),
+				issue.CreatedUser.Name,
+				issue.Updated.Time().Format(time.RFC3339),
+				issue.Description,
+			}
+
+			for _, name := range customFields {
+				value := ""
+
+				for _, field := range issue.CustomFields {
+					if field.Name == name {
+						value = customFieldValue(field.Value)
+					}
+				}
+
+				record = append(record, value)
+			}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+
+		w.Flush()
+
+		if err := w.Error(); err != nil {
+			return err
+		}
+
+		fmt.Printf("exported %d issue(s) to %s\n", len(issues), issueExportCSVFlag)
+
+		return nil
+	},
+}
+
+var issueImportDryRunFlag bool
+var issueImportCommand = &cobra.Command{
+	Use: "import",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		projectKey := args[0]
+		path := args[1]
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		records, err := csv.NewReader(file).ReadAll()
+		file.Close()
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return fmt.Errorf("%s has no header", path)
+		}
+
+		header := records[0]
+		columns := map[string]int{}
+
+		for i, name := range header {
+			columns[normalizeCSVColumn(name)] = i
+		}
+		if _, ok := columns["key"]; !ok {
+			columns["key"] = len(header)
+			records[0] = append(records[0], "key")
+		}
+
+		get := func(record []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		// Custom fields need IDs depending on their types, so their columns are reported instead of being silently dropped.
+		for i, name := range header {
+			if !strings.HasPrefix(normalizeCSVColumn(name), "custom:") {
+				continue
+			}
+			for _, record := range records[1:] {
+				if i < len(record) && strings.TrimSpace(record[i]) != "" && get(record, "key") == "" {
+					fmt.Fprintf(os.Stderr, "warning: column %q is not imported\n", header[i])
+					break
+				}
+			}
+		}
+
+		created := 0
+
+		defer func() {
+			if created > 0 {
+				if err := writeCSV(path, records); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
+		}()
+
+		for n, record := range records[1:] {
+			if key := get(record, "key"); key != "" {
+				fmt.Printf("skipped line %d (already imported as %s)\n", n+2, key)
+				continue
+			}
+
+			fo := issueFrontmatterOption{
+				Summary:   get(record, "summary"),
+				Project:   projectKey,
+				Parent:    get(record, "parent"),
+				Type:      get(record, "type"),
+				Priority:  get(record, "priority"),
+				Status:    get(record, "status"),
+				Assignee:  get(record, "assignee"),
+				Start:     get(record, "start"),
+				Due:       get(record, "due"),
+				Estimated: get(record, "estimated"),
+				Actual:    get(record, "actual"),
+				Content:   get(record, "description"),
+			}
+
+			query, err := buildIssueQuery("", fo, nil)
+			if err != nil {
+				return fmt.Errorf("line %d: %v", n+2, err)
+			}
+			if err := addCSVMilestones(query, projectKey, get(record, "milestone")); err != nil {
+				return fmt.Errorf("line %d: %v", n+2, err)
+			}
+			if issueImportDryRunFlag {
+				fmt.Printf("line %d:\n", n+2)
+				printQuery(query)
+				continue
+			}
+
+			issue, err := client.CreateIssue(query)
+			if err != nil {
+				return fmt.Errorf("line %d: %v", n+2, err)
+			}
+			if err := writeIssue(issue); err != nil {
+				return err
+			}
+
+			for len(record) <= columns["key"] {
+				record = append(record, "")
+			}
+
+			record[columns["key"]] = issue.IssueKey
+			records[n+1] = record
+			created++
+
+			fmt.Printf("created %s (line %d)\n", issue.IssueKey, n+2)
+		}
+
+		return nil
+	},
+}
+
+// addCSVMilestones adds the milestones written as comma separated names in the same way as export.
+func addCSVMilestones(query url.Values, projectKey, value string) error {
+	if value == "" {
+		return nil
+	}
+	if err := fetchProjectByProjectKey(projectKey); err != nil {
+		return err
+	}
+
+	project, err := readProjectByProjectKey(projectKey)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		milestone, err := readMilestoneByName(project.Id, strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+
+		query.Add("milestoneId[]", fmt.Sprint(milestone.Id))
+	}
+
+	return nil
+}
+
+// normalizeCSVColumn maps the column names in CSV into the ones of issueCSVColumns.
+func normalizeCSVColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch name {
+	case "issue key", "issuekey":
+		return "key"
+	case "subject", "title":
+		return "summary"
+	case "issue type", "issuetype", "tracker":
+		return "type"
+	case "start date", "startdate":
+		return "start"
+	case "due date", "duedate", "limitdate":
+		return "due"
+	case "estimated hours", "estimatedhours":
+		return "estimated"
+	case "actual hours", "actualhours":
+		return "actual"
+	case "content", "body":
+		return "description"
+	}
+
+	return name
+}
+
+func writeCSV(path string, records [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func formatCSVDate(t time.Time) string {
+	if t.Equal(time.Time{}) {
+		return ""
+	}
+
+	return t.Format("2006-01-02")
+}
+
+// customFieldValue converts the value of custom field, which may be a string, a number, an item or a list of items, into string.
+func customFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		if name, ok := v["name"]; ok {
+			return fmt.Sprint(name)
+		}
+	case []interface{}:
+		ss := []string{}
+
+		for _, item := range v {
+			ss = append(ss, customFieldValue(item))
+		}
+
+		return strings.Join(ss, ",")
+	}
+
+	return fmt.Sprint(value)
+}
+
+func init() {
+	issueExportCommand.Flags().StringVarP(&issueExportCSVFlag, "csv", "", "", "path of CSV file")
+	issueImportCommand.Flags().BoolVarP(&issueImportDryRunFlag, "dry-run", "n", false, "print the requests without sending them")
+
+	issueCommand.AddCommand(issueExportCommand)
+	issueCommand.AddCommand(issueImportCommand)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFieldValue(t *testing.T) {
+	assert.Equal(t, "", customFieldValue(nil))
+	assert.Equal(t, "foo", customFieldValue("foo"))
+	assert.Equal(t, "1.5", customFieldValue(1.5))
+	assert.Equal(t, "High", customFieldValue(map[string]interface{}{"id": 1.0, "name": "High"}))
+	assert.Equal(t, "A,B", customFieldValue([]interface{}{
+		map[string]interface{}{"name": "A"},
+		map[string]interface{}{"name": "B"},
+	}))
+}
+
+func TestNormalizeCSVColumn(t *testing.T) {
+	assert.Equal(t, "key", normalizeCSVColumn("Issue Key"))
+	assert.Equal(t, "due", normalizeCSVColumn("Due Date"))
+	assert.Equal(t, "custom:foo", normalizeCSVColumn("custom:foo"))
+}
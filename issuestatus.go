@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+// The IDs of built-in statuses, which are the same in all spaces while their names depend on the language.
+const (
+	openStatusId   = 1
+	closedStatusId = 4
+)
+
+var issueStatusCommentFlag string
+var issueStatusCommand = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"move"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		if err := fetchStatuses(); err != nil {
+			return err
+		}
+
+		statuses, err := readStatuses()
+		if err != nil {
+			return err
+		}
+
+		status, err := findStatusByName(statuses, args[1])
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("statusId", fmt.Sprint(status.Id))
+
+		return updateIssueStatus(args[0], query, issueStatusCommentFlag)
+	},
+}
+
+var (
+	issueCloseResolutionFlag string
+	issueCloseCommentFlag    string
+)
+var issueCloseCommand = &cobra.Command{
+	Use: "close",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		query := url.Values{}
+		query.Add("statusId", fmt.Sprint(closedStatusId))
+
+		if issueCloseResolutionFlag != "" {
+			if err := fetchResolutions(); err != nil {
+				return err
+			}
+
+			resolutions, err := readResolutions()
+			if err != nil {
+				return err
+			}
+
+			resolution, err := findResolutionByName(resolutions, issueCloseResolutionFlag)
+			if err != nil {
+				return err
+			}
+
+			query.Add("resolutionId", fmt.Sprint(resolution.Id))
+		}
+
+		return updateIssueStatus(args[0], query, issueCloseCommentFlag)
+	},
+}
+
+var issueReopenCommentFlag string
+var issueReopenCommand = &cobra.Command{
+	Use: "reopen",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		query := url.Values{}
+		query.Add("statusId", fmt.Sprint(openStatusId))
+
+		return updateIssueStatus(args[0], query, issueReopenCommentFlag)
+	},
+}
+
+// updateIssueStatus sends only the given fields and the comment, leaving the other fields of the issue untouched.
+func updateIssueStatus(issueKey string, query url.Values, comment string) error {
+	if comment != "" {
+		query.Add("comment", comment)
+	}
+
+	issue, err := client.UpdateIssue(issueKey, query)
+	if err != nil {
+		return err
+	}
+	if err := writeIssue(issue); err != nil {
+		return err
+	}
+
+	fmt.Printf("updated %s (%s)\n", issue.IssueKey, issue.Status.Name)
+
+	return nil
+}
+
+func init() {
+	issueStatusCommand.Flags().StringVarP(&issueStatusCommentFlag, "comment", "c", "", "add the comment")
+	issueCloseCommand.Flags().StringVarP(&issueCloseResolutionFlag, "resolution", "r", "", "resolution such as Fixed")
+	issueCloseCommand.Flags().StringVarP(&issueCloseCommentFlag, "comment", "c", "", "add the comment")
+	issueReopenCommand.Flags().StringVarP(&issueReopenCommentFlag, "comment", "c", "", "add the comment")
+
+	issueCommand.AddCommand(issueStatusCommand)
+	issueCommand.AddCommand(issueCloseCommand)
+	issueCommand.AddCommand(issueReopenCommand)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+var issueTreeRollupFlag bool
+var issueTreeCommand = &cobra.Command{
+	Use: "tree",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		issueKey := args[0]
+
+		if err := fetchIssue(issueKey); err != nil {
+			return err
+		}
+
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return err
+		}
+
+		ancestors := []backlog.Issue{}
+
+		for parentId := issue.ParentIssueId; parentId != 0; {
+			if err := fetchIssue(fmt.Sprint(parentId)); err != nil {
+				return err
+			}
+
+			parent, err := readIssue(fmt.Sprint(parentId))
+			if err != nil {
+				return err
+			}
+
+			ancestors = append([]backlog.Issue{parent}, ancestors...)
+			parentId = parent.ParentIssueId
+		}
+		for depth, ancestor := range ancestors {
+			printIssueTreeLine(ancestor, depth, false)
+		}
+
+		estimated, actual, err := printIssueSubtree(issue, len(ancestors), true)
+		if err != nil {
+			return err
+		}
+		if issueTreeRollupFlag {
+			fmt.Printf("total: estimated %.2fh, actual %.2fh\n", estimated, actual)
+		}
+
+		return nil
+	},
+}
+
+var issueChildrenCommand = &cobra.Command{
+	Use: "children",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		issueKey := args[0]
+
+		if err := fetchIssue(issueKey); err != nil {
+			return err
+		}
+
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return err
+		}
+
+		children, err := readChildIssues(issue)
+		if err != nil {
+			return err
+		}
+
+		for _, child := range children {
+			printIssueTreeLine(child, 0, false)
+		}
+
+		return nil
+	},
+}
+
+// printIssueSubtree prints the issue and its descendants, and returns the sum of their hours.
+func printIssueSubtree(issue backlog.Issue, depth int, current bool) (estimated, actual float64, err error) {
+	printIssueTreeLine(issue, depth, current)
+
+	estimated = issue.EstimatedHours
+	actual = issue.ActualHours
+
+	children, err := readChildIssues(issue)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, child := range children {
+		e, a, err := printIssueSubtree(child, depth+1, false)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		estimated += e
+		actual += a
+	}
+
+	return estimated, actual, nil
+}
+
+func printIssueTreeLine(issue backlog.Issue, depth int, current bool) {
+	marker := "-"
+
+	if current {
+		marker = "*"
+	}
+
+	assignee := issue.Assignee.Name
+
+	if assignee == "" {
+		assignee = "unassigned"
+	}
+
+	fmt.Printf("%s%s [%s] (%s) %s (%s)\n", strings.Repeat("  ", depth), marker, issue.IssueKey, issue.Status.Name, issue.Summary, assignee)
+}
+
+// readChildIssues fetches the issues whose parent is the given one.
+func readChildIssues(issue backlog.Issue) (children []backlog.Issue, err error) {
+	query := url.Values{}
+	query.Add("parentIssueId[]", fmt.Sprint(issue.Id))
+
+	if err := fetchIssues(issue.ProjectId, query); err != nil {
+		return nil, err
+	}
+
+	issues, err := readIssues(issue.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range issues {
+		if i.ParentIssueId == issue.Id {
+			children = append(children, i)
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Id < children[j].Id
+	})
+
+	return children, nil
+}
+
+func init() {
+	issueTreeCommand.Flags().BoolVarP(&issueTreeRollupFlag, "rollup", "r", false, "sum the estimated and actual hours of the subtree")
+
+	issueCommand.AddCommand(issueTreeCommand)
+	issueCommand.AddCommand(issueChildrenCommand)
+}
@@ -72,3 +72,13 @@ func readIssueTypes(projectId uint64) (issueTypes []backlog.IssueType, err error
 
 	return issueTypes, nil
 }
+
+func findIssueTypeByName(issueTypes []backlog.IssueType, name string) (issueType backlog.IssueType, err error) {
+	for _, t := range issueTypes {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	return issueType, fmt.Errorf("issue type not found: %s", name)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// messageCatalog holds the messages for each language.
+// The English messages are used when a message is missing in the current language.
+var messageCatalog = map[string]map[string]string{
+	"en": {
+		"comment.found":        "found %d comment(s)",
+		"comment.changed":      "%s changed the issue.",
+		"comment.notified":     "notified: %s",
+		"change.none":          "(none)",
+		"change.attached":      "attached %s",
+		"change.detached":      "removed %s",
+		"format.date":          "Jan 2, 2006",
+		"field.summary":        "Subject",
+		"field.description":    "Description",
+		"field.status":         "Status",
+		"field.assigner":       "Assignee",
+		"field.priority":       "Priority",
+		"field.issueType":      "Issue Type",
+		"field.startDate":      "Start Date",
+		"field.limitDate":      "Due Date",
+		"field.estimatedHours": "Estimated Hours",
+		"field.actualHours":    "Actual Hours",
+		"field.component":      "Category",
+		"field.version":        "Version",
+		"field.milestone":      "Milestone",
+		"field.resolution":     "Resolution",
+		"field.parentIssue":    "Parent Issue",
+		"field.attachment":     "Attachment",
+		"field.notification":   "Notification",
+	},
+	"ja": {
+		"comment.found":        "%d 件のコメントが見つかりました",
+		"comment.changed":      "%sが課題の内容を変更しました。",
+		"comment.notified":     "お知らせ: %s",
+		"change.none":          "(未設定)",
+		"change.attached":      "%s を追加しました",
+		"change.detached":      "%s を削除しました",
+		"format.date":          "2006/01/02",
+		"field.summary":        "件名",
+		"field.description":    "詳細",
+		"field.status":         "状態",
+		"field.assigner":       "担当者",
+		"field.priority":       "優先度",
+		"field.issueType":      "種別",
+		"field.startDate":      "開始日",
+		"field.limitDate":      "期限日",
+		"field.estimatedHours": "予定時間",
+		"field.actualHours":    "実績時間",
+		"field.component":      "カテゴリー",
+		"field.version":        "発生バージョン",
+		"field.milestone":      "マイルストーン",
+		"field.resolution":     "完了理由",
+		"field.parentIssue":    "親課題",
+		"field.attachment":     "添付ファイル",
+		"field.notification":   "お知らせ",
+	},
+}
+
+// currentLanguage returns the language set by $BACKLOG_LANG or $LANG, such as "ja" for ja_JP.UTF-8.
+func currentLanguage() string {
+	for _, name := range []string{"BACKLOG_LANG", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		ss := strings.FieldsFunc(value, func(r rune) bool {
+			return r == '_' || r == '-' || r == '.'
+		})
+		if len(ss) == 0 {
+			continue
+		}
+
+		language := strings.ToLower(ss[0])
+
+		if _, ok := messageCatalog[language]; ok {
+			return language
+		}
+	}
+
+	return "en"
+}
+
+// localize returns the message for the key in the current language.
+// The key itself is returned when no message is found.
+func localize(key string) string {
+	if message, ok := messageCatalog[currentLanguage()][key]; ok {
+		return message
+	}
+	if message, ok := messageCatalog["en"][key]; ok {
+		return message
+	}
+
+	return key
+}
@@ -4,64 +4,136 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/ericaro/frontmatter"
 	backlog "github.com/moutend/go-backlog"
+	yaml "gopkg.in/yaml.v2"
 )
 
 type issueFrontmatterOption struct {
-	Summary   string `fm:"summary"`
-	Project   string `fm:"project"`
-	Parent    string `fm:"parent"`
-	Type      string `fm:"type"`
-	Priority  string `fm:"priority"`
-	Status    string `fm:"status"`
-	Start     string `fm:"start"`
-	Due       string `fm:"due"`
-	Estimated string `fm:"estimated"`
-	Actual    string `fm:"actual"`
-	Content   string `fm:"content"`
+	Summary     string   `fm:"summary"`
+	Project     string   `fm:"project"`
+	Parent      string   `fm:"parent"`
+	Type        string   `fm:"type"`
+	Priority    string   `fm:"priority"`
+	Status      string   `fm:"status"`
+	Assignee    string   `fm:"assignee"`
+	Category    []string `fm:"category"`
+	Start       string   `fm:"start"`
+	Due         string   `fm:"due"`
+	Estimated   string   `fm:"estimated"`
+	Actual      string   `fm:"actual"`
+	Attachments []string `fm:"attachments"`
+	Updated     string   `fm:"updated"`
+	Content     string   `fm:"content"`
 }
 
-func parseIssueMarkdown(issueKey, path string) (url.Values, error) {
+// parseIssueMarkdown returns the query and the paths of files to attach, which are resolved against the directory of the file.
+// The files whose names are already attached to the issue are skipped, so that updating with the same file again does not attach them twice.
+func parseIssueMarkdown(issueKey, path string) (url.Values, []string, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var fo issueFrontmatterOption
 
 	if err := frontmatter.Unmarshal(data, &fo); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var (
-		myself      backlog.User
-		project     backlog.Project
-		issue       backlog.Issue
-		parentIssue backlog.Issue
-		issueType   backlog.IssueType
-		priority    backlog.Priority
-		status      backlog.Status
-	)
+	var keys map[string]bool
 
-	if err := fetchMyself(); err != nil {
-		return nil, err
+	if issueKey != "" {
+		keys, err = readFrontmatterKeys(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.TrimSpace(fo.Content) != "" {
+			keys["content"] = true
+		}
 	}
 
-	myself, err = readMyself()
+	query, err := buildIssueQuery(issueKey, fo, keys)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if err := fetchProjectByProjectKey(fo.Project); err != nil {
-		return nil, err
+	attached := map[string]bool{}
+
+	if issueKey != "" {
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, attachment := range issue.Attachments {
+			attached[attachment.Name] = true
+		}
 	}
 
-	project, err = readProjectByProjectKey(fo.Project)
-	if err != nil {
+	attachments := []string{}
+
+	for _, attachment := range resolveAttachmentPaths(fo.Attachments, filepath.Dir(path)) {
+		if !attached[filepath.Base(attachment)] {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	return query, attachments, nil
+}
+
+// readFrontmatterKeys returns the set of keys written in the frontmatter, including the ones whose values are null or empty.
+func readFrontmatterKeys(data []byte) (keys map[string]bool, err error) {
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+	keys = map[string]bool{}
+
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return keys, nil
+	}
+
+	header := []string{}
+
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+
+		header = append(header, line)
+	}
+
+	m := map[string]interface{}{}
+
+	if err := yaml.Unmarshal([]byte(strings.Join(header, "\n")), &m); err != nil {
 		return nil, err
 	}
+	for key := range m {
+		keys[key] = true
+	}
+
+	return keys, nil
+}
+
+// buildIssueQuery resolves the names in the frontmatter into IDs.
+// When keys is nil, the query is built for creating an issue and the empty fields are omitted.
+// Otherwise only the fields in keys are sent, and the null or empty ones are cleared, so that a partial file never clobbers the rest of the issue.
+// The description is cleared by writing "description:" with an empty body.
+func buildIssueQuery(issueKey string, fo issueFrontmatterOption, keys map[string]bool) (url.Values, error) {
+	var (
+		project backlog.Project
+		issue   backlog.Issue
+		err     error
+	)
+
+	present := func(key, value string) bool {
+		if keys == nil {
+			return value != ""
+		}
+
+		return keys[key]
+	}
 
 	if issueKey != "" {
 		if err := fetchIssue(issueKey); err != nil {
@@ -73,134 +145,275 @@ func parseIssueMarkdown(issueKey, path string) (url.Values, error) {
 			return nil, err
 		}
 	}
+	if fo.Project != "" {
+		if err := fetchProjectByProjectKey(fo.Project); err != nil {
+			return nil, err
+		}
 
-	if fo.Parent != "" {
-		if err := fetchIssue(fo.Parent); err != nil {
+		project, err = readProjectByProjectKey(fo.Project)
+		if err != nil {
+			return nil, err
+		}
+	} else if issue.Id != 0 {
+		if err := fetchProjectById(issue.ProjectId); err != nil {
 			return nil, err
 		}
 
-		parentIssue, err = readIssue(fo.Parent)
+		project, err = readProjectById(issue.ProjectId)
 		if err != nil {
 			return nil, err
 		}
-	}
-	if err := fetchIssueTypes(project.Id); err != nil {
-		return nil, err
+	} else {
+		return nil, fmt.Errorf("project is required")
 	}
 
-	issueTypes, err := readIssueTypes(project.Id)
-	if err != nil {
-		return nil, err
+	values := url.Values{}
+
+	if issueKey == "" {
+		values.Add("projectId", fmt.Sprint(project.Id))
 	}
-	for _, issueType = range issueTypes {
-		if fo.Type == issueType.Name {
-			break
-		}
+	if present("summary", fo.Summary) {
+		values.Add("summary", fo.Summary)
 	}
-
-	if err := fetchPriorities(); err != nil {
-		return nil, err
+	if present("content", fo.Content) {
+		values.Add("description", fo.Content)
+	} else if present("description", "") {
+		values.Add("description", "")
 	}
-
-	priorities, err := readPriorities()
-	if err != nil {
-		return nil, err
+	if present("estimated", fo.Estimated) {
+		values.Add("estimatedHours", fo.Estimated)
 	}
-	for _, priority = range priorities {
-		if fo.Priority == priority.Name {
-			break
+	if present("actual", fo.Actual) {
+		values.Add("actualHours", fo.Actual)
+	}
+	if present("start", fo.Start) {
+		values.Add("startDate", fo.Start)
+	}
+	if present("due", fo.Due) {
+		values.Add("dueDate", fo.Due)
+	}
+	if present("parent", fo.Parent) {
+		if fo.Parent == "" {
+			values.Add("parentIssueId", "")
+		} else {
+			if err := fetchIssue(fo.Parent); err != nil {
+				return nil, err
+			}
+
+			parentIssue, err := readIssue(fo.Parent)
+			if err != nil {
+				return nil, err
+			}
+
+			values.Add("parentIssueId", fmt.Sprint(parentIssue.Id))
 		}
 	}
+	if present("type", fo.Type) && fo.Type != "" {
+		if err := fetchIssueTypes(project.Id); err != nil {
+			return nil, err
+		}
 
-	if err := fetchStatuses(); err != nil {
-		return nil, err
-	}
+		issueTypes, err := readIssueTypes(project.Id)
+		if err != nil {
+			return nil, err
+		}
 
-	statuses, err := readStatuses()
-	if err != nil {
-		return nil, err
-	}
-	for _, status = range statuses {
-		if fo.Status == status.Name {
-			break
+		issueType, err := findIssueTypeByName(issueTypes, fo.Type)
+		if err != nil {
+			return nil, err
 		}
+
+		values.Add("issueTypeId", fmt.Sprint(issueType.Id))
 	}
+	if present("priority", fo.Priority) && fo.Priority != "" {
+		if err := fetchPriorities(); err != nil {
+			return nil, err
+		}
 
-	values := url.Values{}
-	values.Add("summary", fo.Summary)
-	values.Add("description", fo.Content)
-	values.Add("estimatedHours", fmt.Sprint(fo.Estimated))
-	values.Add("actualHours", fmt.Sprint(fo.Actual))
+		priorities, err := readPriorities()
+		if err != nil {
+			return nil, err
+		}
+
+		priority, err := findPriorityByName(priorities, fo.Priority)
+		if err != nil {
+			return nil, err
+		}
 
-	if priority.Id > 0 {
 		values.Add("priorityId", fmt.Sprint(priority.Id))
 	}
-	if status.Id > 0 {
+	if present("status", fo.Status) && fo.Status != "" {
+		if err := fetchStatuses(); err != nil {
+			return nil, err
+		}
+
+		statuses, err := readStatuses()
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := findStatusByName(statuses, fo.Status)
+		if err != nil {
+			return nil, err
+		}
+
 		values.Add("statusId", fmt.Sprint(status.Id))
 	}
-	if issue.Id != 0 {
-		values.Add("assigneeId", fmt.Sprint(issue.Assignee.Id))
-	}
-	if issueKey == "" {
-		values.Add("assigneeId", fmt.Sprint(myself.Id))
-	}
-	if parentIssue.Id != 0 {
-		values.Add("parentIssueId", fmt.Sprint(parentIssue.Id))
+	if len(fo.Category) > 0 {
+		if err := fetchCategories(project.Id); err != nil {
+			return nil, err
+		}
 
+		categories, err := readCategories(project.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range fo.Category {
+			category, err := findCategoryByName(categories, name)
+			if err != nil {
+				return nil, err
+			}
+
+			values.Add("categoryId[]", fmt.Sprint(category.Id))
+		}
+	} else if present("category", "") {
+		values.Add("categoryId[]", "")
 	}
-	if fo.Due != "" {
-		values.Add("dueDate", fo.Due)
-	}
-	if fo.Start != "" {
-		values.Add("startDate", fo.Start)
+
+	switch {
+	case present("assignee", fo.Assignee) && fo.Assignee != "":
+		if err := fetchUsers(project.Id); err != nil {
+			return nil, err
+		}
+
+		users, err := readUsers(project.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		assignee, err := findUserByName(users, fo.Assignee)
+		if err != nil {
+			return nil, err
+		}
+
+		values.Add("assigneeId", fmt.Sprint(assignee.Id))
+	case present("assignee", fo.Assignee):
+		values.Add("assigneeId", "")
+	case issueKey == "":
+		if err := fetchMyself(); err != nil {
+			return nil, err
+		}
+
+		myself, err := readMyself()
+		if err != nil {
+			return nil, err
+		}
+
+		values.Add("assigneeId", fmt.Sprint(myself.Id))
 	}
 
 	return values, nil
 }
 
 type pullRequestFrontmatterOption struct {
-	Summary    string `fm:"summary"`
-	Project    string `fm:"project"`
-	Repository string `fm:"repository"`
-	Issue      string `fm:"issue"`
-	Base       string `fm:"base"`
-	Branch     string `fm:"branch"`
-	Content    string `fm:"content"`
+	Summary     string   `fm:"summary"`
+	Project     string   `fm:"project"`
+	Repository  string   `fm:"repository"`
+	Issue       string   `fm:"issue"`
+	Base        string   `fm:"base"`
+	Branch      string   `fm:"branch"`
+	Assignee    string   `fm:"assignee"`
+	Notify      []string `fm:"notify"`
+	Attachments []string `fm:"attachments"`
+	Content     string   `fm:"content"`
 }
 
-func parsePullRequestMarkdown(path string) (url.Values, error) {
+// parsePullRequestMarkdown returns the query and the paths of files to attach, which are resolved against dir.
+func parsePullRequestMarkdown(path, dir string) (url.Values, []string, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var fo pullRequestFrontmatterOption
 
 	if err := frontmatter.Unmarshal(data, &fo); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if fo.Project == "" || fo.Repository == "" {
+		remote, err := readGitRemote()
+		if err != nil {
+			return nil, nil, err
+		}
+		if fo.Project == "" {
+			fo.Project = remote.ProjectKey
+		}
+		if fo.Repository == "" {
+			fo.Repository = remote.RepositoryName
+		}
+	}
+	if fo.Base == "" {
+		fo.Base = readGitDefaultBranch()
+	}
+	if fo.Branch == "" {
+		fo.Branch, err = readGitCurrentBranch()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	var (
-		myself backlog.User
-		issue  backlog.Issue
+		myself   backlog.User
+		project  backlog.Project
+		assignee backlog.User
+		issue    backlog.Issue
+		users    []backlog.User
 	)
 
 	if err := fetchMyself(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	myself, err = readMyself()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	assignee = myself
+
+	if fo.Assignee != "" || len(fo.Notify) > 0 {
+		if err := fetchProjectByProjectKey(fo.Project); err != nil {
+			return nil, nil, err
+		}
+
+		project, err = readProjectByProjectKey(fo.Project)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := fetchUsers(project.Id); err != nil {
+			return nil, nil, err
+		}
+
+		users, err = readUsers(project.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if fo.Assignee != "" {
+		assignee, err = findUserByName(users, fo.Assignee)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 	if fo.Issue != "" {
 		if err := fetchIssue(fo.Issue); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		issue, err = readIssue(fo.Issue)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -211,8 +424,120 @@ func parsePullRequestMarkdown(path string) (url.Values, error) {
 	values.Add("description", fo.Content)
 	values.Add("base", fo.Base)
 	values.Add("branch", fo.Branch)
-	values.Add("issueId", fmt.Sprint(issue.Id))
-	values.Add("assigneeId", fmt.Sprint(myself.Id))
+	values.Add("assigneeId", fmt.Sprint(assignee.Id))
+
+	for _, name := range fo.Notify {
+		user, err := findUserByName(users, name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		values.Add("notifiedUserId[]", fmt.Sprint(user.Id))
+	}
+	if issue.Id != 0 {
+		values.Add("issueId", fmt.Sprint(issue.Id))
+	}
+
+	return values, resolveAttachmentPaths(fo.Attachments, dir), nil
+}
+
+type wikiFrontmatterOption struct {
+	Project string `fm:"project"`
+	Name    string `fm:"name"`
+	Content string `fm:"content"`
+}
+
+func parseWikiMarkdown(path string) (url.Values, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fo wikiFrontmatterOption
+
+	if err := frontmatter.Unmarshal(data, &fo); err != nil {
+		return nil, err
+	}
+	if fo.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	values := url.Values{}
+	values.Add("project", fo.Project)
+	values.Add("name", fo.Name)
+	values.Add("content", fo.Content)
 
 	return values, nil
 }
+
+// generatePullRequestMarkdown builds the pull request template from the commits between the base and the current branch.
+func generatePullRequestMarkdown() (string, error) {
+	remote, err := readGitRemote()
+	if err != nil {
+		return "", err
+	}
+
+	base := readGitDefaultBranch()
+
+	branch, err := readGitCurrentBranch()
+	if err != nil {
+		return "", err
+	}
+
+	ref := "origin/" + base
+	if _, err := runGit("rev-parse", "--verify", "--quiet", ref); err != nil {
+		ref = base
+	}
+
+	subjects, bodies, err := readGitCommitMessages(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var summary, description string
+
+	switch len(subjects) {
+	case 0:
+		summary = branch
+	case 1:
+		summary = subjects[0]
+		description = bodies[0]
+	default:
+		summary = branch
+		for _, subject := range subjects {
+			description += fmt.Sprintf("- %s\n", subject)
+		}
+	}
+
+	s := "---\n"
+	s += fmt.Sprintf("summary: %q\n", summary)
+	s += fmt.Sprintf("project: %s\n", remote.ProjectKey)
+	s += fmt.Sprintf("repository: %s\n", remote.RepositoryName)
+	s += fmt.Sprintf("issue: %s\n", findIssueKey(branch))
+	s += fmt.Sprintf("base: %s\n", base)
+	s += fmt.Sprintf("branch: %s\n", branch)
+	s += "assignee:\n"
+	s += "notify: []\n"
+	s += "attachments: []\n"
+	s += "---\n"
+	s += strings.TrimSpace(description) + "\n"
+
+	return s, nil
+}
+
+// printQuery prints the query sorted by key, one value per line.
+func printQuery(query url.Values) {
+	keys := []string{}
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range query[key] {
+			fmt.Printf("%s: %q\n", key, value)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+var milestoneCommand = &cobra.Command{
+	Use:     "milestone",
+	Aliases: []string{"version"},
+	RunE: func(c *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var milestoneListAllFlag bool
+var milestoneListCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := fetchMilestones(project.Id); err != nil {
+			return err
+		}
+
+		milestones, err := readMilestones(project.Id)
+		if err != nil {
+			return err
+		}
+
+		sortMilestones(milestones)
+
+		for _, milestone := range milestones {
+			if milestone.Archived && !milestoneListAllFlag {
+				continue
+			}
+
+			fmt.Printf("- %s (%s - %s)", milestone.Name, formatCSVDate(milestone.StartDate.Time()), formatCSVDate(milestone.ReleaseDueDate.Time()))
+
+			if milestone.Archived {
+				fmt.Print(" [archived]")
+			}
+
+			fmt.Println()
+		}
+
+		return nil
+	},
+}
+
+var milestoneShowCommand = &cobra.Command{
+	Use:     "show",
+	Aliases: []string{"s"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		milestone, err := readMilestoneByName(project.Id, args[1])
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("milestoneId[]", fmt.Sprint(milestone.Id))
+
+		if err := fetchIssues(project.Id, query); err != nil {
+			return err
+		}
+
+		cached, err := readIssues(project.Id)
+		if err != nil {
+			return err
+		}
+
+		issues := []backlog.Issue{}
+		closed := 0
+
+		for _, issue := range cached {
+			if !hasMilestone(issue, milestone.Id) {
+				continue
+			}
+			if issue.Status.Id == closedStatusId {
+				closed++
+			}
+
+			issues = append(issues, issue)
+		}
+
+		sort.Slice(issues, func(i, j int) bool {
+			return issues[i].Id < issues[j].Id
+		})
+
+		fmt.Println("name:", milestone.Name)
+		fmt.Println("start:", formatCSVDate(milestone.StartDate.Time()))
+		fmt.Println("release:", formatCSVDate(milestone.ReleaseDueDate.Time()))
+		fmt.Println("archived:", milestone.Archived)
+
+		if len(issues) > 0 {
+			fmt.Printf("progress: %d/%d closed (%d%%)\n", closed, len(issues), closed*100/len(issues))
+		} else {
+			fmt.Println("progress: no issues")
+		}
+		if milestone.Description != "" {
+			fmt.Println()
+			fmt.Println(milestone.Description)
+		}
+
+		fmt.Println()
+
+		for _, issue := range issues {
+			fmt.Printf("- [%s] (%s) %s\n", issue.IssueKey, issue.Status.Name, issue.Summary)
+		}
+
+		return nil
+	},
+}
+
+var (
+	milestoneCreateStartFlag       string
+	milestoneCreateReleaseFlag     string
+	milestoneCreateDescriptionFlag string
+)
+var milestoneCreateCommand = &cobra.Command{
+	Use:     "create",
+	Aliases: []string{"c"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("name", args[1])
+
+		if milestoneCreateDescriptionFlag != "" {
+			query.Add("description", milestoneCreateDescriptionFlag)
+		}
+		for key, value := range map[string]string{
+			"startDate":      milestoneCreateStartFlag,
+			"releaseDueDate": milestoneCreateReleaseFlag,
+		} {
+			if value == "" {
+				continue
+			}
+
+			t, err := parseDate(value)
+			if err != nil {
+				return err
+			}
+
+			query.Add(key, t.Format("2006-01-02"))
+		}
+
+		milestone, err := client.CreateVersion(fmt.Sprint(project.Id), query)
+		if err != nil {
+			return err
+		}
+		if err := writeMilestone(milestone); err != nil {
+			return err
+		}
+
+		fmt.Println("created", milestone.Name)
+
+		return nil
+	},
+}
+
+var milestoneCloseCommand = &cobra.Command{
+	Use:     "close",
+	Aliases: []string{"archive"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		project, err := resolveProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		milestone, err := readMilestoneByName(project.Id, args[1])
+		if err != nil {
+			return err
+		}
+
+		// The API requires the name even when only the archived state changes.
+		query := url.Values{}
+		query.Add("name", milestone.Name)
+		query.Add("archived", "true")
+
+		milestone, err = client.UpdateVersion(fmt.Sprint(project.Id), fmt.Sprint(milestone.Id), query)
+		if err != nil {
+			return err
+		}
+		if err := writeMilestone(milestone); err != nil {
+			return err
+		}
+
+		fmt.Println("archived", milestone.Name)
+
+		return nil
+	},
+}
+
+func resolveProject(projectKey string) (project backlog.Project, err error) {
+	if err := fetchProjectByProjectKey(projectKey); err != nil {
+		return project, err
+	}
+
+	return readProjectByProjectKey(projectKey)
+}
+
+func readMilestoneByName(projectId uint64, name string) (milestone backlog.Version, err error) {
+	milestone, ok, err := lookupMilestone(projectId, name)
+	if err != nil {
+		return milestone, err
+	}
+	if !ok {
+		return milestone, fmt.Errorf("milestone not found: %s", name)
+	}
+
+	return milestone, nil
+}
+
+// lookupMilestone finds the milestone of the project by name.
+// The milestones are fetched again once when the cache has no such name, since it may have been created or renamed since.
+func lookupMilestone(projectId uint64, name string) (milestone backlog.Version, ok bool, err error) {
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			if err := clearMilestones(projectId); err != nil {
+				return milestone, false, err
+			}
+		}
+		if err := fetchMilestones(projectId); err != nil {
+			return milestone, false, err
+		}
+
+		milestones, err := readMilestones(projectId)
+		if err != nil {
+			return milestone, false, err
+		}
+		if milestone, err := findMilestoneByName(milestones, name); err == nil {
+			return milestone, true, nil
+		}
+	}
+
+	return milestone, false, nil
+}
+
+// sortMilestones orders the milestones by release date, putting the ones without it last.
+func sortMilestones(milestones []backlog.Version) {
+	sort.SliceStable(milestones, func(i, j int) bool {
+		a := milestones[i].ReleaseDueDate.Time()
+		b := milestones[j].ReleaseDueDate.Time()
+
+		switch {
+		case a.IsZero() != b.IsZero():
+			return b.IsZero()
+		case !a.Equal(b):
+			return a.Before(b)
+		default:
+			return milestones[i].Id < milestones[j].Id
+		}
+	})
+}
+
+func hasMilestone(issue backlog.Issue, milestoneId uint64) bool {
+	for _, milestone := range issue.Milestone {
+		if milestone.Id == milestoneId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fetchMilestones(projectId uint64) error {
+	q := url.Values{}
+	q.Add("projectId", fmt.Sprint(projectId))
+
+	if time.Now().Sub(lastExecuted(MilestonesCache, q)) < 5*time.Minute {
+		return nil
+	}
+
+	milestones, err := client.GetVersions(fmt.Sprint(projectId))
+	if err != nil {
+		return err
+	}
+
+	// The cached set of the project is replaced, so that the deleted milestones do not remain.
+	if err := removeMilestones(projectId); err != nil {
+		return err
+	}
+	for _, milestone := range milestones {
+		if err := writeMilestone(milestone); err != nil {
+			return err
+		}
+	}
+	if err := setLastExecuted(MilestonesCache, q); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// clearMilestones expires the cached milestones of the project, so that the next fetchMilestones gets them from the server.
+func clearMilestones(projectId uint64) error {
+	q := url.Values{}
+	q.Add("projectId", fmt.Sprint(projectId))
+
+	path, err := lastExecutedPath(MilestonesCache, q)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func removeMilestones(projectId uint64) error {
+	base, err := cachePath(MilestonesCache)
+	if err != nil {
+		return err
+	}
+
+	milestones, err := readMilestones(projectId)
+	if err != nil {
+		return err
+	}
+	for _, milestone := range milestones {
+		path := filepath.Join(base, fmt.Sprintf("%d.json", milestone.Id))
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeMilestone(milestone backlog.Version) error {
+	base, err := cachePath(MilestonesCache)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(base, 0755)
+
+	data, err := json.Marshal(milestone)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", milestone.Id))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readMilestones(projectId uint64) (milestones []backlog.Version, err error) {
+	base, err := cachePath(MilestonesCache)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
+		if !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var milestone backlog.Version
+
+		if err := json.Unmarshal(data, &milestone); err != nil {
+			return err
+		}
+		if milestone.ProjectId == projectId {
+			milestones = append(milestones, milestone)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return milestones, nil
+}
+
+func findMilestoneByName(milestones []backlog.Version, name string) (milestone backlog.Version, err error) {
+	for _, m := range milestones {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+
+	return milestone, fmt.Errorf("milestone not found: %s", name)
+}
+
+func init() {
+	milestoneListCommand.Flags().BoolVarP(&milestoneListAllFlag, "all", "a", false, "include archived milestones")
+	milestoneCreateCommand.Flags().StringVar(&milestoneCreateStartFlag, "start", "", "start date")
+	milestoneCreateCommand.Flags().StringVar(&milestoneCreateReleaseFlag, "release", "", "release due date")
+	milestoneCreateCommand.Flags().StringVar(&milestoneCreateDescriptionFlag, "description", "", "description")
+
+	milestoneCommand.AddCommand(milestoneListCommand)
+	milestoneCommand.AddCommand(milestoneShowCommand)
+	milestoneCommand.AddCommand(milestoneCreateCommand)
+	milestoneCommand.AddCommand(milestoneCloseCommand)
+
+	rootCommand.AddCommand(milestoneCommand)
+}
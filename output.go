@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isTerminal reports whether the standard output is a terminal.
+func isTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns $COLUMNS or 80.
+func terminalWidth() int {
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return 80
+}
+
+var colorCodes = []int{31, 32, 33, 34, 35, 36}
+
+// colorize wraps the text with the ANSI color picked by the hash of the text, so that the same text always gets the same color.
+func colorize(text string) string {
+	h := fnv.New32a()
+	h.Write([]byte(text))
+
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", colorCodes[h.Sum32()%uint32(len(colorCodes))], text)
+}
+
+// runeWidth returns 2 for East Asian wide characters and 1 for others.
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115f,
+		r >= 0x2e80 && r <= 0xa4cf,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xfe30 && r <= 0xfe4f,
+		r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6:
+		return 2
+	}
+
+	return 1
+}
+
+func stringWidth(s string) (width int) {
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+
+	return width
+}
+
+// wrapText wraps the lines of Markdown longer than the width.
+// Code blocks, indented lines and tables are left as they are.
+func wrapText(text string, width int) string {
+	lines := []string{}
+	inCodeBlock := false
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+		if inCodeBlock || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "|") || stringWidth(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+
+		lines = append(lines, wrapLine(line, width)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var linePrefixPattern = regexp.MustCompile(`^\s*(?:(?:[-*+]|[0-9]+[.)]|>)\s+)*`)
+
+// wrapLine wraps the line keeping its indentation and list marker.
+// The continuation lines are indented to the text after the marker, or prefixed with ">" in blockquotes.
+func wrapLine(line string, width int) (lines []string) {
+	prefix := linePrefixPattern.FindString(line)
+	indent := strings.Repeat(" ", stringWidth(prefix))
+
+	if strings.HasPrefix(strings.TrimSpace(prefix), ">") {
+		indent = prefix
+	}
+
+	words := strings.Fields(line[len(prefix):])
+
+	// Give up keeping the prefix when it leaves little room for the text.
+	if stringWidth(prefix) > width/2 {
+		words = strings.Fields(line)
+		prefix, indent = "", ""
+	}
+
+	current := prefix
+	currentWidth := stringWidth(prefix)
+	empty := true
+
+	for _, word := range words {
+		wordWidth := stringWidth(word)
+
+		if !empty && currentWidth+1+wordWidth <= width {
+			current += " " + word
+			currentWidth += 1 + wordWidth
+			continue
+		}
+		if !empty {
+			lines = append(lines, current)
+			current, currentWidth, empty = indent, stringWidth(indent), true
+		}
+		for _, r := range word {
+			if !empty && currentWidth+runeWidth(r) > width {
+				lines = append(lines, current)
+				current, currentWidth = indent, stringWidth(indent)
+			}
+
+			current += string(r)
+			currentWidth += runeWidth(r)
+			empty = false
+		}
+	}
+	if !empty {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// printWithPager prints the output through $PAGER when the standard output is a terminal.
+func printWithPager(output *bytes.Buffer) error {
+	if !isTerminal() {
+		_, err := output.WriteTo(os.Stdout)
+		return err
+	}
+
+	// A blank $PAGER is treated as unset.
+	ss := strings.Fields(os.Getenv("PAGER"))
+	if len(ss) == 0 {
+		ss = []string{"less", "-R"}
+	}
+
+	cmd := exec.Command(ss[0], ss[1:]...)
+	cmd.Stdin = output
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// parseDate parses the date given as YYYY-MM-DD or RFC3339 in the local time zone.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// confirm asks the question on the terminal and reports whether the answer is yes.
+// The terminal is opened directly, so that it works even when the standard input is piped.
+func confirm(question string) bool {
+	var input io.Reader = os.Stdin
+
+	if tty, err := os.Open("/dev/tty"); err == nil {
+		defer tty.Close()
+		input = tty
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+
+	answer, _ := bufio.NewReader(input).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapText(t *testing.T) {
+	assert.Equal(t, "the quick brown fox\njumps over the lazy\ndog", wrapText("the quick brown fox jumps over the lazy dog", 20))
+	assert.Equal(t, "```\nthis code line is very long\n```", wrapText("```\nthis code line is very long\n```", 10))
+	assert.Equal(t, "日本語の\n文章です", wrapText("日本語の文章です", 8))
+	assert.Equal(t, "- the quick brown\n  fox jumps over", wrapText("- the quick brown fox jumps over", 18))
+	assert.Equal(t, "  * the quick\n    brown fox", wrapText("  * the quick brown fox", 14))
+	assert.Equal(t, "1. the quick\n   brown fox", wrapText("1. the quick brown fox", 12))
+	assert.Equal(t, "> the quick\n> brown fox", wrapText("> the quick brown fox", 12))
+	assert.Equal(t, "    if err != nil { return err }\n    x := 1", wrapText("    if err != nil { return err }\n    x := 1", 10))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -60,3 +61,13 @@ func readPriorities() (priorities []backlog.Priority, err error) {
 
 	return priorities, nil
 }
+
+func findPriorityByName(priorities []backlog.Priority, name string) (priority backlog.Priority, err error) {
+	for _, p := range priorities {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return priority, fmt.Errorf("priority not found: %s", name)
+}
@@ -23,15 +23,25 @@ var pullRequestCommand = &cobra.Command{
 	},
 }
 
+var (
+	pullRequestListStatusFlag   string
+	pullRequestListAssigneeFlag string
+	pullRequestListAuthorFlag   string
+	pullRequestListMineFlag     bool
+	pullRequestListIssueFlag    string
+)
 var pullRequestListCommand = &cobra.Command{
-	Use: "list",
+	Use:     "list",
+	Aliases: []string{"l"},
 	RunE: func(c *cobra.Command, args []string) error {
-		if len(args) < 2 {
-			return nil
+		if pullRequestListMineFlag && pullRequestListAssigneeFlag != "" {
+			return fmt.Errorf("--mine and --assignee cannot be used together")
 		}
 
-		projectKey := args[0]
-		repositoryName := args[1]
+		projectKey, repositoryName, err := resolveProjectAndRepository(args)
+		if err != nil {
+			return err
+		}
 
 		if err := fetchProjectByProjectKey(projectKey); err != nil {
 			return err
@@ -51,7 +61,75 @@ var pullRequestListCommand = &cobra.Command{
 			return err
 		}
 
-		if err := fetchPullRequests(project.Id, repository.Id); err != nil {
+		var (
+			statusId uint64
+			assignee backlog.User
+			author   backlog.User
+			issue    backlog.Issue
+		)
+
+		query := url.Values{}
+
+		if pullRequestListStatusFlag != "" {
+			statusId, err = pullRequestStatusId(pullRequestListStatusFlag)
+			if err != nil {
+				return err
+			}
+
+			query.Add("statusId[]", fmt.Sprint(statusId))
+		}
+		if pullRequestListAssigneeFlag != "" || pullRequestListAuthorFlag != "" {
+			if err := fetchUsers(project.Id); err != nil {
+				return err
+			}
+
+			users, err := readUsers(project.Id)
+			if err != nil {
+				return err
+			}
+			if pullRequestListAssigneeFlag != "" {
+				assignee, err = findUserByName(users, pullRequestListAssigneeFlag)
+				if err != nil {
+					return err
+				}
+			}
+			if pullRequestListAuthorFlag != "" {
+				author, err = findUserByName(users, pullRequestListAuthorFlag)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if pullRequestListMineFlag {
+			if err := fetchMyself(); err != nil {
+				return err
+			}
+
+			assignee, err = readMyself()
+			if err != nil {
+				return err
+			}
+		}
+		if assignee.Id != 0 {
+			query.Add("assigneeId[]", fmt.Sprint(assignee.Id))
+		}
+		if author.Id != 0 {
+			query.Add("createdUserId[]", fmt.Sprint(author.Id))
+		}
+		if pullRequestListIssueFlag != "" {
+			if err := fetchIssue(pullRequestListIssueFlag); err != nil {
+				return err
+			}
+
+			issue, err = readIssue(pullRequestListIssueFlag)
+			if err != nil {
+				return err
+			}
+
+			query.Add("issueId[]", fmt.Sprint(issue.Id))
+		}
+
+		if err := fetchPullRequests(project.Id, repository.Id, query); err != nil {
 			return err
 		}
 
@@ -65,27 +143,77 @@ var pullRequestListCommand = &cobra.Command{
 		})
 
 		for _, pullRequest := range pullRequests {
-			fmt.Printf("%d. %s (created at %s by %s)\n", pullRequest.Number, pullRequest.Summary, pullRequest.Created.Time().Format("2006-01-02"), pullRequest.CreatedUser.Name)
+			if statusId != 0 && statusId != pullRequest.Status.Id {
+				continue
+			}
+			if assignee.Id != 0 && assignee.Id != pullRequest.Assignee.Id {
+				continue
+			}
+			if author.Id != 0 && author.Id != pullRequest.CreatedUser.Id {
+				continue
+			}
+			if issue.Id != 0 && issue.Id != pullRequest.Issue.Id {
+				continue
+			}
+
+			fmt.Printf(
+				"%d. (%s) %s <- %s %s",
+				pullRequest.Number,
+				pullRequest.Status.Name,
+				pullRequest.Base,
+				pullRequest.Branch,
+				pullRequest.Summary,
+			)
+			if pullRequest.Issue.IssueKey != "" {
+				fmt.Printf(" [%s]", pullRequest.Issue.IssueKey)
+			}
+			fmt.Printf(" (created at %s by %s)\n", pullRequest.Created.Time().Format("2006-01-02"), pullRequest.CreatedUser.Name)
 		}
 
 		return nil
 	},
 }
 
+var (
+	pullRequestCreateFillFlag   bool
+	pullRequestCreateDryRunFlag bool
+)
 var pullRequestCreateCommand = &cobra.Command{
 	Use:     "create",
 	Aliases: []string{"c"},
 	RunE: func(c *cobra.Command, args []string) error {
-		if len(args) < 1 {
-			return nil
+		var path, dir string
+
+		if pullRequestCreateFillFlag {
+			text, err := generatePullRequestMarkdown()
+			if err != nil {
+				return err
+			}
+
+			path, err = editTempFile("backlog-pullrequest-*.md", text)
+			defer os.Remove(path)
+			if err != nil {
+				return err
+			}
+
+			// The temporary file lives elsewhere, so the attachments are relative to the working directory.
+			dir = "."
+		} else {
+			if len(args) < 1 {
+				return nil
+			}
+
+			path = args[0]
+			dir = filepath.Dir(path)
 		}
 
-		path := args[0]
-
-		query, err := parsePullRequestMarkdown(path)
+		query, attachmentPaths, err := parsePullRequestMarkdown(path, dir)
 		if err != nil {
 			return err
 		}
+		if query.Get("summary") == "" {
+			return fmt.Errorf("aborted due to empty summary")
+		}
 
 		project := query.Get("project")
 		repository := query.Get("repository")
@@ -93,6 +221,24 @@ var pullRequestCreateCommand = &cobra.Command{
 		query.Del("project")
 		query.Del("repository")
 
+		if pullRequestCreateDryRunFlag {
+			if err := addAttachmentPlaceholders(query, attachmentPaths); err != nil {
+				return err
+			}
+
+			fmt.Printf("POST /api/v2/projects/%s/git/repositories/%s/pullRequests\n", project, repository)
+			printQuery(query)
+
+			return nil
+		}
+
+		attachments, err := uploadAttachments(attachmentPaths)
+		if err != nil {
+			return err
+		}
+
+		addAttachmentIds(query, attachments)
+
 		pullRequest, err := client.CreatePullRequest(project, repository, query)
 		if err != nil {
 			return err
@@ -104,8 +250,11 @@ var pullRequestCreateCommand = &cobra.Command{
 	},
 }
 
-func fetchPullRequests(projectId, repositoryId uint64) error {
+func fetchPullRequests(projectId, repositoryId uint64, query url.Values) error {
 	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
 	q.Add("projectId", fmt.Sprint(projectId))
 	q.Add("repositoryId", fmt.Sprint(repositoryId))
 
@@ -113,7 +262,7 @@ func fetchPullRequests(projectId, repositoryId uint64) error {
 		return nil
 	}
 
-	pullRequests, err := client.GetPullRequests(fmt.Sprint(projectId), fmt.Sprint(repositoryId), nil)
+	pullRequests, err := client.GetPullRequests(fmt.Sprint(projectId), fmt.Sprint(repositoryId), query)
 	if err != nil {
 		return err
 	}
@@ -169,6 +318,20 @@ func fetchPullRequest(projectId, repositoryId uint64, number string) error {
 	return nil
 }
 
+// pullRequestStatusId converts the name of pull request status into the ID used by Backlog.
+func pullRequestStatusId(name string) (uint64, error) {
+	switch strings.ToLower(name) {
+	case "open":
+		return 1, nil
+	case "closed":
+		return 2, nil
+	case "merged":
+		return 3, nil
+	}
+
+	return 0, fmt.Errorf("unknown pull request status: %s", name)
+}
+
 func readPullRequests(projectId, repositoryId uint64) (pullRequests []backlog.PullRequest, err error) {
 	base, err := cachePath(PullRequestsCache)
 	if err != nil {
@@ -238,6 +401,15 @@ func readPullRequest(projectId, repositoryId uint64, number string) (pullRequest
 }
 
 func init() {
+	pullRequestListCommand.Flags().StringVarP(&pullRequestListStatusFlag, "status", "s", "", "pick pull requests by status (open, closed or merged)")
+	pullRequestListCommand.Flags().StringVarP(&pullRequestListAssigneeFlag, "assignee", "a", "", "pick pull requests assigned to the user")
+	pullRequestListCommand.Flags().StringVarP(&pullRequestListAuthorFlag, "author", "", "", "pick pull requests created by the user")
+	pullRequestListCommand.Flags().BoolVarP(&pullRequestListMineFlag, "mine", "m", false, "pick pull requests assigned to myself")
+	pullRequestListCommand.Flags().StringVarP(&pullRequestListIssueFlag, "issue", "i", "", "pick pull requests linked to the issue")
+
+	pullRequestCreateCommand.Flags().BoolVarP(&pullRequestCreateFillFlag, "fill", "f", false, "generate the pull request from the commits and edit it with $EDITOR")
+	pullRequestCreateCommand.Flags().BoolVarP(&pullRequestCreateDryRunFlag, "dry-run", "n", false, "print the request without sending it")
+
 	pullRequestCommand.AddCommand(pullRequestListCommand)
 	pullRequestCommand.AddCommand(pullRequestCreateCommand)
 
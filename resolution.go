@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+)
+
+func fetchResolutions() error {
+	if time.Now().Sub(lastExecuted(ResolutionsCache, nil)) < 365*24*time.Hour {
+		return nil
+	}
+
+	resolutions, err := client.GetResolutions()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(resolutions)
+	if err != nil {
+		return err
+	}
+
+	base, err := cachePath(ResolutionsCache)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(base, 0755)
+
+	path := filepath.Join(base, "resolutions.json")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	if err := setLastExecuted(ResolutionsCache, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readResolutions() (resolutions []backlog.Resolution, err error) {
+	base, err := cachePath(ResolutionsCache)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(base, "resolutions.json")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &resolutions); err != nil {
+		return nil, err
+	}
+
+	return resolutions, nil
+}
+
+func findResolutionByName(resolutions []backlog.Resolution, name string) (resolution backlog.Resolution, err error) {
+	for _, r := range resolutions {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+
+	return resolution, fmt.Errorf("resolution not found: %s", name)
+}
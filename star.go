@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+var starCommand = &cobra.Command{
+	Use: "stars",
+	RunE: func(c *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var starListUserFlag string
+var starListCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l"},
+	RunE: func(c *cobra.Command, args []string) error {
+		user, err := resolveUser(starListUserFlag)
+		if err != nil {
+			return err
+		}
+
+		if err := fetchStars(user.Id); err != nil {
+			return err
+		}
+
+		stars, err := readStars(user.Id)
+		if err != nil {
+			return err
+		}
+
+		sort.Slice(stars, func(i, j int) bool {
+			return stars[i].Created.Time().After(stars[j].Created.Time())
+		})
+
+		for _, star := range stars {
+			fmt.Printf("- %s (starred at %s)\n", star.Title, star.Created.Time().Format("2006-01-02"))
+			fmt.Printf("  %s\n", star.Url)
+		}
+
+		return nil
+	},
+}
+
+var issueStarCommand = &cobra.Command{
+	Use: "star",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		issueKey := args[0]
+
+		if err := fetchIssue(issueKey); err != nil {
+			return err
+		}
+
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("issueId", fmt.Sprint(issue.Id))
+
+		if err := addStar(query); err != nil {
+			return err
+		}
+
+		fmt.Println("starred", issue.IssueKey)
+
+		return nil
+	},
+}
+
+// commentStarCommand takes the issue or the pull request before the comment ID, since the same ID cannot tell which one the comment belongs to.
+var commentStarCommand = &cobra.Command{
+	Use: "star",
+	RunE: func(c *cobra.Command, args []string) error {
+		target, rest, err := readCommentTarget(args)
+		if err != nil {
+			return err
+		}
+		if len(rest) < 1 {
+			return fmt.Errorf("specify comment ID")
+		}
+
+		commentId, err := strconv.ParseUint(rest[0], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+
+		if target.IsPullRequest() {
+			query.Add("pullRequestCommentId", fmt.Sprint(commentId))
+		} else {
+			query.Add("commentId", fmt.Sprint(commentId))
+		}
+		if err := addStar(query); err != nil {
+			return err
+		}
+
+		fmt.Println("starred comment", commentId)
+
+		return nil
+	},
+}
+
+var pullRequestStarCommand = &cobra.Command{
+	Use: "star",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		number := args[len(args)-1]
+
+		projectKey, repositoryName, err := resolveProjectAndRepository(args[:len(args)-1])
+		if err != nil {
+			return err
+		}
+
+		if err := fetchProjectByProjectKey(projectKey); err != nil {
+			return err
+		}
+
+		project, err := readProjectByProjectKey(projectKey)
+		if err != nil {
+			return err
+		}
+
+		if err := fetchRepository(project.Id, repositoryName); err != nil {
+			return err
+		}
+
+		repository, err := readRepository(project.Id, repositoryName)
+		if err != nil {
+			return err
+		}
+
+		if err := fetchPullRequest(project.Id, repository.Id, number); err != nil {
+			return err
+		}
+
+		pullRequest, err := readPullRequest(project.Id, repository.Id, number)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("pullRequestId", fmt.Sprint(pullRequest.Id))
+
+		if err := addStar(query); err != nil {
+			return err
+		}
+
+		fmt.Println("starred pull request", pullRequest.Number)
+
+		return nil
+	},
+}
+
+// resolveUser finds the user by "me", the numeric ID or the name among the members of all projects.
+func resolveUser(name string) (user backlog.User, err error) {
+	if name == "" || name == "me" {
+		if err := fetchMyself(); err != nil {
+			return user, err
+		}
+
+		return readMyself()
+	}
+	if id, err := strconv.ParseUint(name, 10, 64); err == nil {
+		user.Id = id
+		return user, nil
+	}
+
+	if err := fetchProjects(); err != nil {
+		return user, err
+	}
+
+	projects, err := readProjects()
+	if err != nil {
+		return user, err
+	}
+	for _, project := range projects {
+		if err := fetchUsers(project.Id); err != nil {
+			return user, err
+		}
+
+		users, err := readUsers(project.Id)
+		if err != nil {
+			return user, err
+		}
+		if user, err := findUserByName(users, name); err == nil {
+			return user, nil
+		}
+	}
+
+	return user, fmt.Errorf("user not found: %s", name)
+}
+
+// addStar adds the star and expires the cached stars of myself, so that stars list shows it right away.
+func addStar(query url.Values) error {
+	if err := client.AddStar(query); err != nil {
+		return err
+	}
+	if err := fetchMyself(); err != nil {
+		return err
+	}
+
+	myself, err := readMyself()
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Add("userId", fmt.Sprint(myself.Id))
+
+	path, err := lastExecutedPath(StarsCache, q)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func fetchStars(userId uint64) error {
+	q := url.Values{}
+	q.Add("userId", fmt.Sprint(userId))
+
+	if time.Now().Sub(lastExecuted(StarsCache, q)) < 30*time.Minute {
+		return nil
+	}
+
+	query := url.Values{}
+	query.Add("count", "100")
+
+	stars, err := client.GetUserStars(userId, query)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(stars)
+	if err != nil {
+		return err
+	}
+
+	base, err := cachePath(StarsCache)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(base, 0755)
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", userId))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	if err := setLastExecuted(StarsCache, q); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readStars(userId uint64) (stars []backlog.Star, err error) {
+	base, err := cachePath(StarsCache)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", userId))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &stars); err != nil {
+		return nil, err
+	}
+
+	return stars, nil
+}
+
+func init() {
+	starListCommand.Flags().StringVarP(&starListUserFlag, "user", "u", "me", "user who starred (me, user ID or name)")
+
+	starCommand.AddCommand(starListCommand)
+	issueCommand.AddCommand(issueStarCommand)
+	commentCommand.AddCommand(commentStarCommand)
+	pullRequestCommand.AddCommand(pullRequestStarCommand)
+
+	rootCommand.AddCommand(starCommand)
+}
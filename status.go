@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -59,3 +60,13 @@ func readStatuses() (statuses []backlog.Status, err error) {
 
 	return statuses, nil
 }
+
+func findStatusByName(statuses []backlog.Status, name string) (status backlog.Status, err error) {
+	for _, s := range statuses {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+
+	return status, fmt.Errorf("status not found: %s", name)
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+var issueLogTimeCommentFlag string
+var issueLogTimeCommand = &cobra.Command{
+	Use: "log-time",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		issueKey := args[0]
+
+		hours, err := parseHours(args[1])
+		if err != nil {
+			return err
+		}
+
+		// Refetch the issue so that the hours logged by others in the meantime are not lost.
+		if err := fetchIssue(issueKey); err != nil {
+			return err
+		}
+
+		issue, err := readIssue(issueKey)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("actualHours", formatHours(issue.ActualHours+hours))
+
+		if issueLogTimeCommentFlag != "" {
+			query.Add("comment", issueLogTimeCommentFlag)
+		}
+
+		issue, err = client.UpdateIssue(issueKey, query)
+		if err != nil {
+			return err
+		}
+		if err := writeIssue(issue); err != nil {
+			return err
+		}
+
+		fmt.Printf("logged %sh to %s (actual: %sh)\n", formatHours(hours), issue.IssueKey, formatHours(issue.ActualHours))
+
+		return nil
+	},
+}
+
+// parseHours accepts hours such as 1.5, 1.5h, 90m and 1h30m.
+func parseHours(s string) (float64, error) {
+	if hours, err := strconv.ParseFloat(s, 64); err == nil {
+		return hours, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours: %s", s)
+	}
+
+	return d.Hours(), nil
+}
+
+// formatHours rounds the hours to two decimal places, which is the precision kept by Backlog.
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(math.Round(hours*100)/100, 'f', -1, 64)
+}
+
+// formatOptionalHours returns an empty string for 0, since the API reports unset hours as 0.
+// Writing them back as 0 would turn the unset hours into 0.
+func formatOptionalHours(hours float64) string {
+	if hours == 0 {
+		return ""
+	}
+
+	return formatHours(hours)
+}
+
+type timeEntry struct {
+	Date  string
+	Issue backlog.Issue
+	Hours float64
+}
+
+// hoursChange is a change of actualHours recorded in a comment.
+type hoursChange struct {
+	UserId        uint64
+	Created       time.Time
+	OriginalValue string
+	NewValue      string
+}
+
+func readHoursChanges(comments []backlog.Comment) (changes []hoursChange) {
+	for _, comment := range comments {
+		for _, change := range comment.ChangeLog {
+			if change.Field != "actualHours" {
+				continue
+			}
+
+			changes = append(changes, hoursChange{
+				UserId:        comment.CreatedUser.Id,
+				Created:       comment.Created.Time(),
+				OriginalValue: change.OriginalValue,
+				NewValue:      change.NewValue,
+			})
+		}
+	}
+
+	return changes
+}
+
+// collectTimeEntries reconstructs the hours logged by the user from the changes of actualHours.
+// Only the changes made in [from, to) are counted.
+func collectTimeEntries(issue backlog.Issue, changes []hoursChange, userId uint64, from, to time.Time) (entries []timeEntry) {
+	for _, change := range changes {
+		if change.UserId != userId || change.Created.Before(from) || !change.Created.Before(to) {
+			continue
+		}
+
+		orig, _ := strconv.ParseFloat(change.OriginalValue, 64)
+		updated, _ := strconv.ParseFloat(change.NewValue, 64)
+
+		if updated == orig {
+			continue
+		}
+
+		entries = append(entries, timeEntry{
+			Date:  change.Created.Local().Format("2006-01-02"),
+			Issue: issue,
+			Hours: updated - orig,
+		})
+	}
+
+	return entries
+}
+
+// sumHoursByDate returns the total hours of each date.
+func sumHoursByDate(entries []timeEntry) map[string]float64 {
+	totals := map[string]float64{}
+
+	for _, entry := range entries {
+		totals[entry.Date] += entry.Hours
+	}
+
+	return totals
+}
+
+var (
+	timesheetUserFlag   string
+	timesheetFromFlag   string
+	timesheetToFlag     string
+	timesheetFormatFlag string
+)
+var timesheetCommand = &cobra.Command{
+	Use: "timesheet",
+	RunE: func(c *cobra.Command, args []string) error {
+		user, err := resolveUser(timesheetUserFlag)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		to := now
+
+		if timesheetFromFlag != "" {
+			if from, err = parseDate(timesheetFromFlag); err != nil {
+				return err
+			}
+		}
+		if timesheetToFlag != "" {
+			if to, err = parseDate(timesheetToFlag); err != nil {
+				return err
+			}
+		}
+
+		// The --to date is inclusive.
+		to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+		if err := fetchProjects(); err != nil {
+			return err
+		}
+
+		projects, err := readProjects()
+		if err != nil {
+			return err
+		}
+
+		updatedIssues := []backlog.Issue{}
+
+		for _, project := range projects {
+			issues, err := readIssues(project.Id)
+			if err != nil {
+				return err
+			}
+			for _, issue := range issues {
+				// Logging hours always updates the issue, so the ones untouched since from have nothing to count.
+				if issue.Updated.Time().Before(from) {
+					continue
+				}
+				if err := fetchIssueCommentsSince(issue.Id, from); err != nil {
+					return err
+				}
+
+				updatedIssues = append(updatedIssues, issue)
+			}
+		}
+
+		comments, err := readIssueCommentsByIssue()
+		if err != nil {
+			return err
+		}
+
+		entries := []timeEntry{}
+
+		for _, issue := range updatedIssues {
+			entries = append(entries, collectTimeEntries(issue, readHoursChanges(comments[issue.Id]), user.Id, from, to)...)
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Date != entries[j].Date {
+				return entries[i].Date < entries[j].Date
+			}
+
+			return entries[i].Issue.IssueKey < entries[j].Issue.IssueKey
+		})
+
+		switch timesheetFormatFlag {
+		case "csv":
+			return printTimesheetCSV(entries)
+		case "table":
+			printTimesheetTable(entries)
+		default:
+			return fmt.Errorf("unknown format: %s", timesheetFormatFlag)
+		}
+
+		return nil
+	},
+}
+
+func printTimesheetTable(entries []timeEntry) {
+	var total float64
+
+	totals := sumHoursByDate(entries)
+
+	for i, entry := range entries {
+		fmt.Printf("%s  %-12s %6s  %s\n", entry.Date, entry.Issue.IssueKey, formatHours(entry.Hours), entry.Issue.Summary)
+
+		total += entry.Hours
+
+		if i == len(entries)-1 || entries[i+1].Date != entry.Date {
+			fmt.Printf("%s  %-12s %6s\n", entry.Date, "(total)", formatHours(totals[entry.Date]))
+		}
+	}
+
+	fmt.Printf("total: %sh\n", formatHours(total))
+}
+
+func printTimesheetCSV(entries []timeEntry) error {
+	w := csv.NewWriter(os.Stdout)
+
+	if err := w.Write([]string{"date", "key", "summary", "hours"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		record := []string{
+			entry.Date,
+			entry.Issue.IssueKey,
+			strings.TrimSpace(entry.Issue.Summary),
+			formatHours(entry.Hours),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func init() {
+	issueLogTimeCommand.Flags().StringVarP(&issueLogTimeCommentFlag, "comment", "c", "", "add the comment")
+
+	timesheetCommand.Flags().StringVarP(&timesheetUserFlag, "user", "u", "me", "user name, user ID or me")
+	timesheetCommand.Flags().StringVar(&timesheetFromFlag, "from", "", "first date (default: the first day of this month)")
+	timesheetCommand.Flags().StringVar(&timesheetToFlag, "to", "", "last date (default: today)")
+	timesheetCommand.Flags().StringVarP(&timesheetFormatFlag, "format", "f", "table", "output format (table or csv)")
+
+	issueCommand.AddCommand(issueLogTimeCommand)
+	rootCommand.AddCommand(timesheetCommand)
+}
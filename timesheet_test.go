@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHours(t *testing.T) {
+	for input, expected := range map[string]float64{
+		"2":     2,
+		"1.5":   1.5,
+		"1.5h":  1.5,
+		"90m":   1.5,
+		"1h30m": 1.5,
+	} {
+		hours, err := parseHours(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, hours, input)
+	}
+
+	_, err := parseHours("abc")
+	assert.Error(t, err)
+}
+
+func TestFormatHours(t *testing.T) {
+	assert.Equal(t, "1.5", formatHours(1.5))
+	assert.Equal(t, "0.33", formatHours(1.0/3))
+	assert.Equal(t, "3", formatHours(1.25+1.75))
+}
+
+func TestCollectTimeEntries(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 4, day, hour, 0, 0, 0, time.Local)
+	}
+
+	issue := backlog.Issue{IssueKey: "PROJ-1"}
+	from := at(1, 0)
+	to := at(3, 0)
+
+	for _, test := range []struct {
+		name     string
+		changes  []hoursChange
+		expected []timeEntry
+	}{
+		{
+			name: "from is inclusive and to is exclusive",
+			changes: []hoursChange{
+				{UserId: 1, Created: at(1, 0), OriginalValue: "", NewValue: "1"},
+				{UserId: 1, Created: from.Add(-time.Second), OriginalValue: "1", NewValue: "2"},
+				{UserId: 1, Created: to, OriginalValue: "2", NewValue: "3"},
+				{UserId: 1, Created: to.Add(-time.Second), OriginalValue: "3", NewValue: "3.5"},
+			},
+			expected: []timeEntry{
+				{Date: "2024-04-01", Issue: issue, Hours: 1},
+				{Date: "2024-04-02", Issue: issue, Hours: 0.5},
+			},
+		},
+		{
+			name: "several entries on one day",
+			changes: []hoursChange{
+				{UserId: 1, Created: at(2, 9), OriginalValue: "1", NewValue: "2.5"},
+				{UserId: 1, Created: at(2, 17), OriginalValue: "2.5", NewValue: "3"},
+			},
+			expected: []timeEntry{
+				{Date: "2024-04-02", Issue: issue, Hours: 1.5},
+				{Date: "2024-04-02", Issue: issue, Hours: 0.5},
+			},
+		},
+		{
+			name: "other users and unchanged hours are ignored",
+			changes: []hoursChange{
+				{UserId: 2, Created: at(2, 9), OriginalValue: "1", NewValue: "2"},
+				{UserId: 1, Created: at(2, 10), OriginalValue: "2", NewValue: "2"},
+				{UserId: 1, Created: at(2, 11), OriginalValue: "2", NewValue: "1"},
+			},
+			expected: []timeEntry{
+				{Date: "2024-04-02", Issue: issue, Hours: -1},
+			},
+		},
+	} {
+		assert.Equal(t, test.expected, collectTimeEntries(issue, test.changes, 1, from, to), test.name)
+	}
+}
+
+func TestSumHoursByDate(t *testing.T) {
+	entries := []timeEntry{
+		{Date: "2024-04-01", Hours: 1},
+		{Date: "2024-04-01", Hours: 0.5},
+		{Date: "2024-04-02", Hours: 2},
+	}
+
+	assert.Equal(t, map[string]float64{"2024-04-01": 1.5, "2024-04-02": 2}, sumHoursByDate(entries))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+)
+
+func fetchUsers(projectId uint64) error {
+	q := url.Values{}
+	q.Add("projectId", fmt.Sprint(projectId))
+
+	if time.Now().Sub(lastExecuted(UsersCache, q)) < 24*time.Hour {
+		return nil
+	}
+
+	users, err := client.GetProjectUsers(fmt.Sprint(projectId), nil)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+
+	base, err := cachePath(UsersCache)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(base, 0755)
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", projectId))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	if err := setLastExecuted(UsersCache, q); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readUsers(projectId uint64) (users []backlog.User, err error) {
+	base, err := cachePath(UsersCache)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", projectId))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// findUserByName finds the user whose name or user ID matches the given one.
+func findUserByName(users []backlog.User, name string) (user backlog.User, err error) {
+	for _, u := range users {
+		if u.Name == name || u.UserId == name {
+			return u, nil
+		}
+	}
+
+	return user, fmt.Errorf("user not found: %s", name)
+}
@@ -24,6 +24,7 @@ var wikiCommand = &cobra.Command{
 	},
 }
 
+var wikiListTreeFlag bool
 var wikiListCommand = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l"},
@@ -49,12 +50,17 @@ var wikiListCommand = &cobra.Command{
 				return err
 			}
 
+			fmt.Printf("- [%s] %s\n", project.ProjectKey, project.Name)
+
+			if wikiListTreeFlag {
+				printWikiTree(buildWikiTree(wikis), 1)
+				continue
+			}
+
 			sort.Slice(wikis, func(i, j int) bool {
 				return wikis[i].Updated.Time().After(wikis[j].Updated.Time())
 			})
 
-			fmt.Printf("- [%s] %s\n", project.ProjectKey, project.Name)
-
 			for _, wiki := range wikis {
 				fmt.Printf("  - %s updated at %s by %s (%d)\n", wiki.Name, wiki.Updated.Time().Format("2006-01-02"), wiki.UpdatedUser.Name, wiki.Id)
 			}
@@ -75,15 +81,15 @@ var wikiShowCommand = &cobra.Command{
 			return nil
 		}
 
-		wikiId, err := strconv.Atoi(args[0])
+		wikiId, err := resolveWikiId(args)
 		if err != nil {
 			return err
 		}
-		if err := fetchWiki(uint64(wikiId)); err != nil {
+		if err := fetchWiki(wikiId); err != nil {
 			return err
 		}
 
-		wiki, err := readWiki(uint64(wikiId))
+		wiki, err := readWiki(wikiId)
 		if err != nil {
 			return err
 		}
@@ -97,27 +103,344 @@ var wikiShowCommand = &cobra.Command{
 			return err
 		}
 
-		{
-			fmt.Println("---")
-			fmt.Println("project:", project.ProjectKey)
-			fmt.Printf("name: %s\n", wiki.Name)
-			fmt.Printf("created: %s\n", wiki.Created.Time().Format("2006-01-02"))
-			fmt.Printf("updated: %s\n", wiki.Updated.Time().Format("2006-01-02"))
+		text, err := renderWikiMarkdown(project, wiki)
+		if err != nil {
+			return err
+		}
 
-			u, err := url.Parse(fmt.Sprintf("https://%s.backlog.jp/wiki/%s/%s", space, project.ProjectKey, wiki.Name))
-			if err != nil {
-				return err
-			}
+		fmt.Println(text)
+
+		return nil
+	},
+}
+
+var wikiMailNotifyFlag bool
+
+var wikiCreateCommand = &cobra.Command{
+	Use:     "create",
+	Aliases: []string{"c"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		path := args[len(args)-1]
+
+		query, err := parseWikiMarkdown(path)
+		if err != nil {
+			return err
+		}
+
+		projectKey := query.Get("project")
+		query.Del("project")
 
-			fmt.Println("url:", u)
-			fmt.Println("---")
-			fmt.Println(wiki.Content)
+		if len(args) > 1 {
+			projectKey = args[0]
 		}
 
+		if err := fetchProjectByProjectKey(projectKey); err != nil {
+			return err
+		}
+
+		project, err := readProjectByProjectKey(projectKey)
+		if err != nil {
+			return err
+		}
+
+		query.Add("projectId", fmt.Sprint(project.Id))
+		query.Add("mailNotify", fmt.Sprint(wikiMailNotifyFlag))
+
+		wiki, err := client.CreateWiki(query)
+		if err != nil {
+			return err
+		}
+		if err := writeWiki(wiki); err != nil {
+			return err
+		}
+
+		fmt.Println("created wiki", wiki.Id)
+
 		return nil
 	},
 }
 
+var wikiUpdateCommand = &cobra.Command{
+	Use:     "update",
+	Aliases: []string{"u"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		wikiId, err := resolveWikiId(args[:len(args)-1])
+		if err != nil {
+			return err
+		}
+
+		return updateWikiWithMarkdown(wikiId, args[len(args)-1])
+	},
+}
+
+var wikiEditCommand = &cobra.Command{
+	Use:     "edit",
+	Aliases: []string{"e"},
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		wikiId, err := resolveWikiId(args)
+		if err != nil {
+			return err
+		}
+
+		// Read the server directly, since the cache may be stale or hold the list entry without the content.
+		wiki, err := client.GetWiki(wikiId)
+		if err != nil {
+			return err
+		}
+		if err := writeWiki(wiki); err != nil {
+			return err
+		}
+
+		if err := fetchProjectById(wiki.ProjectId); err != nil {
+			return err
+		}
+
+		project, err := readProjectById(wiki.ProjectId)
+		if err != nil {
+			return err
+		}
+
+		text, err := renderWikiMarkdown(project, wiki)
+		if err != nil {
+			return err
+		}
+
+		path, err := editTempFile("backlog-wiki-*.md", text)
+		defer os.Remove(path)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if string(data) == text {
+			fmt.Println("no changes")
+			return nil
+		}
+
+		return updateWikiWithMarkdown(wiki.Id, path)
+	},
+}
+
+// renderWikiMarkdown renders the wiki as Markdown with the frontmatter which parseWikiMarkdown understands.
+func renderWikiMarkdown(project backlog.Project, wiki backlog.Wiki) (string, error) {
+	u, err := url.Parse(fmt.Sprintf("https://%s.backlog.jp/wiki/%s/%s", space, project.ProjectKey, wiki.Name))
+	if err != nil {
+		return "", err
+	}
+
+	s := "---\n"
+	s += fmt.Sprintf("project: %s\n", quoteFrontmatter(project.ProjectKey))
+	s += fmt.Sprintf("name: %s\n", quoteFrontmatter(wiki.Name))
+	s += fmt.Sprintf("created: %s\n", quoteFrontmatter(wiki.Created.Time().Format("2006-01-02")))
+	s += fmt.Sprintf("updated: %s\n", quoteFrontmatter(wiki.Updated.Time().Format("2006-01-02")))
+	s += fmt.Sprintf("url: %s\n", quoteFrontmatter(u.String()))
+	s += "---\n"
+	s += wiki.Content
+
+	return s, nil
+}
+
+func updateWikiWithMarkdown(wikiId uint64, path string) error {
+	query, err := parseWikiMarkdown(path)
+	if err != nil {
+		return err
+	}
+
+	query.Del("project")
+	query.Add("mailNotify", fmt.Sprint(wikiMailNotifyFlag))
+
+	wiki, err := client.UpdateWiki(wikiId, query)
+	if err != nil {
+		return err
+	}
+	if err := writeWiki(wiki); err != nil {
+		return err
+	}
+
+	fmt.Println("updated wiki", wiki.Id)
+
+	return nil
+}
+
+type wikiNode struct {
+	name     string
+	wiki     *backlog.Wiki
+	children []*wikiNode
+}
+
+// buildWikiTree builds the hierarchy of wikis from their names separated by "/".
+func buildWikiTree(wikis []backlog.Wiki) *wikiNode {
+	root := &wikiNode{}
+
+	for i := range wikis {
+		node := root
+
+		for _, name := range strings.Split(wikis[i].Name, "/") {
+			var child *wikiNode
+
+			for _, c := range node.children {
+				if c.name == name {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &wikiNode{name: name}
+				node.children = append(node.children, child)
+			}
+
+			node = child
+		}
+
+		node.wiki = &wikis[i]
+	}
+
+	sortWikiTree(root)
+
+	return root
+}
+
+func sortWikiTree(node *wikiNode) {
+	sort.Slice(node.children, func(i, j int) bool {
+		return node.children[i].name < node.children[j].name
+	})
+
+	for _, child := range node.children {
+		sortWikiTree(child)
+	}
+}
+
+func printWikiTree(node *wikiNode, depth int) {
+	for _, child := range node.children {
+		indent := strings.Repeat("  ", depth)
+
+		if child.wiki == nil {
+			fmt.Printf("%s- %s\n", indent, child.name)
+		} else {
+			fmt.Printf("%s- %s (%d)\n", indent, child.name, child.wiki.Id)
+		}
+
+		printWikiTree(child, depth+1)
+	}
+}
+
+// resolveWikiId accepts either WIKI_ID or PROJECT NAME and returns the ID of wiki.
+func resolveWikiId(args []string) (uint64, error) {
+	if len(args) == 1 {
+		wikiId, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("specify wiki ID or project and wiki name")
+		}
+
+		return wikiId, nil
+	}
+
+	projectKey := args[0]
+	name := strings.Join(args[1:], " ")
+
+	if err := fetchProjectByProjectKey(projectKey); err != nil {
+		return 0, err
+	}
+
+	project, err := readProjectByProjectKey(projectKey)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{}
+	query.Add("projectIdOrKey", fmt.Sprint(project.Id))
+
+	if err := fetchWikis(query); err != nil {
+		return 0, err
+	}
+
+	wikis, err := readWikis(project.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	wiki, err := findWikiByName(wikis, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return wiki.Id, nil
+}
+
+// findWikiByName finds the wiki by the exact name first, and then by case insensitive, partial and fuzzy matches.
+// It fails when more than one wiki matches at the same level.
+func findWikiByName(wikis []backlog.Wiki, name string) (wiki backlog.Wiki, err error) {
+	matchers := []func(string) bool{
+		func(s string) bool { return s == name },
+		func(s string) bool { return strings.EqualFold(s, name) },
+		func(s string) bool { return strings.Contains(strings.ToLower(s), strings.ToLower(name)) },
+		func(s string) bool { return isSubsequence(strings.ToLower(name), strings.ToLower(s)) },
+	}
+
+	for _, match := range matchers {
+		candidates := []backlog.Wiki{}
+
+		for _, w := range wikis {
+			if match(w.Name) {
+				candidates = append(candidates, w)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], nil
+		}
+
+		names := []string{}
+
+		for _, candidate := range candidates {
+			names = append(names, candidate.Name)
+		}
+
+		sort.Strings(names)
+
+		return wiki, fmt.Errorf("%q matches more than one wiki: %s", name, strings.Join(names, ", "))
+	}
+
+	return wiki, fmt.Errorf("wiki not found: %s", name)
+}
+
+// isSubsequence reports whether all characters of sub appear in s in the same order.
+func isSubsequence(sub, s string) bool {
+	rs := []rune(s)
+	i := 0
+
+	for _, r := range sub {
+		for i < len(rs) && rs[i] != r {
+			i++
+		}
+		if i == len(rs) {
+			return false
+		}
+
+		i++
+	}
+
+	return true
+}
+
 func fetchWikis(query url.Values) error {
 	if time.Now().Sub(lastExecuted(WikisCache, query)) < 30*time.Minute {
 		return nil
@@ -154,7 +477,10 @@ func fetchWikis(query url.Values) error {
 }
 
 func fetchWiki(wikiId uint64) error {
-	if time.Now().Sub(lastExecuted(WikiCache, nil)) < 30*time.Minute {
+	q := url.Values{}
+	q.Add("wikiId", fmt.Sprint(wikiId))
+
+	if time.Now().Sub(lastExecuted(WikiCache, q)) < 30*time.Minute {
 		return nil
 	}
 
@@ -162,7 +488,17 @@ func fetchWiki(wikiId uint64) error {
 	if err != nil {
 		return err
 	}
+	if err := writeWiki(wiki); err != nil {
+		return err
+	}
+	if err := setLastExecuted(WikiCache, q); err != nil {
+		return err
+	}
 
+	return nil
+}
+
+func writeWiki(wiki backlog.Wiki) error {
 	base, err := cachePath(WikisCache)
 	if err != nil {
 		return err
@@ -179,9 +515,6 @@ func fetchWiki(wikiId uint64) error {
 	if err := ioutil.WriteFile(path, data, 0644); err != nil {
 		return err
 	}
-	if err := setLastExecuted(WikiCache, nil); err != nil {
-		return err
-	}
 
 	return nil
 }
@@ -240,7 +573,13 @@ func readWiki(wikiId uint64) (wiki backlog.Wiki, err error) {
 
 func init() {
 	wikiCommand.AddCommand(wikiListCommand)
+	wikiListCommand.Flags().BoolVarP(&wikiListTreeFlag, "tree", "t", false, "show wikis as a hierarchy")
+	wikiCommand.PersistentFlags().BoolVarP(&wikiMailNotifyFlag, "mail-notify", "", false, "notify the project members by mail")
+
 	wikiCommand.AddCommand(wikiShowCommand)
+	wikiCommand.AddCommand(wikiCreateCommand)
+	wikiCommand.AddCommand(wikiUpdateCommand)
+	wikiCommand.AddCommand(wikiEditCommand)
 
 	rootCommand.AddCommand(wikiCommand)
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ericaro/frontmatter"
+	backlog "github.com/moutend/go-backlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindWikiByName(t *testing.T) {
+	wikis := []backlog.Wiki{
+		{Id: 1, Name: "Home"},
+		{Id: 2, Name: "Runbook/Deploy"},
+		{Id: 3, Name: "Runbook/Rollback"},
+	}
+
+	w1, err := findWikiByName(wikis, "Runbook/Deploy")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), w1.Id)
+
+	w2, err := findWikiByName(wikis, "home")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), w2.Id)
+
+	w3, err := findWikiByName(wikis, "rbk/rllbck")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), w3.Id)
+
+	_, err = findWikiByName(wikis, "Runbook")
+	assert.Error(t, err)
+
+	_, err = findWikiByName(wikis, "Unknown")
+	assert.Error(t, err)
+}
+
+func TestWikiFrontmatterName(t *testing.T) {
+	for _, name := range []string{"Release: 2024", "[draft] Plan", "#tag", "Home"} {
+		data := "---\nproject: " + quoteFrontmatter("PROJ") + "\nname: " + quoteFrontmatter(name) + "\n---\nbody\n"
+
+		var fo wikiFrontmatterOption
+
+		assert.NoError(t, frontmatter.Unmarshal([]byte(data), &fo), name)
+		assert.Equal(t, name, fo.Name)
+		assert.Equal(t, "body\n", fo.Content)
+	}
+}
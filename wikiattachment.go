@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var wikiAttachmentsCommand = &cobra.Command{
+	Use: "attachments",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		wikiId, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		attachments, err := client.GetWikiAttachments(wikiId)
+		if err != nil {
+			return err
+		}
+
+		sharedFiles, err := client.GetWikiSharedFiles(wikiId)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("found %d attachment(s)\n", len(attachments))
+		for _, attachment := range attachments {
+			fmt.Printf("  - %s (%s) (%d)\n", attachment.Name, formatSize(attachment.Size), attachment.Id)
+		}
+
+		fmt.Printf("found %d shared file(s)\n", len(sharedFiles))
+		for _, sharedFile := range sharedFiles {
+			fmt.Printf("  - %s%s (%s) (%d)\n", sharedFile.Dir, sharedFile.Name, formatSize(sharedFile.Size), sharedFile.Id)
+		}
+
+		return nil
+	},
+}
+
+var wikiDownloadOutputFlag string
+var wikiDownloadCommand = &cobra.Command{
+	Use: "download",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return nil
+		}
+
+		wikiId, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		attachmentId, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		attachments, err := client.GetWikiAttachments(wikiId)
+		if err != nil {
+			return err
+		}
+
+		name := ""
+
+		for _, attachment := range attachments {
+			if attachment.Id == attachmentId {
+				name = attachment.Name
+			}
+		}
+		if name == "" {
+			return fmt.Errorf("attachment %d not found", attachmentId)
+		}
+
+		path, err := downloadFile(wikiDownloadOutputFlag, name, func(w io.Writer) error {
+			return client.DownloadWikiAttachment(wikiId, attachmentId, w)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("downloaded", path)
+
+		return nil
+	},
+}
+
+var wikiAttachSharedFileFlag []string
+var wikiAttachCommand = &cobra.Command{
+	Use: "attach",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		wikiId, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		if len(args) > 1 {
+			attachments, err := uploadAttachments(args[1:])
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			addAttachmentIds(query, attachments)
+
+			attachments, err = client.AddWikiAttachments(wikiId, query)
+			if err != nil {
+				return err
+			}
+			for _, attachment := range attachments {
+				fmt.Println("attached", attachment.Name)
+			}
+		}
+		if len(wikiAttachSharedFileFlag) > 0 {
+			query := url.Values{}
+
+			for _, fileId := range wikiAttachSharedFileFlag {
+				query.Add("fileId[]", fileId)
+			}
+
+			sharedFiles, err := client.LinkWikiSharedFiles(wikiId, query)
+			if err != nil {
+				return err
+			}
+			for _, sharedFile := range sharedFiles {
+				fmt.Printf("linked %s%s\n", sharedFile.Dir, sharedFile.Name)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	wikiDownloadCommand.Flags().StringVarP(&wikiDownloadOutputFlag, "output", "o", "", "path or directory to save the attachment")
+	wikiAttachCommand.Flags().StringSliceVarP(&wikiAttachSharedFileFlag, "shared-file", "s", nil, "link the shared files of the project by ID (comma separated)")
+
+	wikiCommand.AddCommand(wikiAttachmentsCommand)
+	wikiCommand.AddCommand(wikiDownloadCommand)
+	wikiCommand.AddCommand(wikiAttachCommand)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+const wikiExportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+</head>
+<body>
+<nav><a href="%s">%s</a></nav>
+<h1>%s</h1>
+%s</body>
+</html>
+`
+
+var (
+	wikiExportFormatFlag string
+	wikiExportOutputFlag string
+)
+var wikiExportCommand = &cobra.Command{
+	Use: "export",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+		if wikiExportFormatFlag != "html" {
+			return fmt.Errorf("unsupported format: %s", wikiExportFormatFlag)
+		}
+
+		projectKey := args[0]
+		dir := wikiExportOutputFlag
+
+		if err := fetchProjectByProjectKey(projectKey); err != nil {
+			return err
+		}
+
+		project, err := readProjectByProjectKey(projectKey)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Add("projectIdOrKey", fmt.Sprint(project.Id))
+
+		if err := fetchWikis(query); err != nil {
+			return err
+		}
+
+		wikis, err := readWikis(project.Id)
+		if err != nil {
+			return err
+		}
+
+		// The pages are written under pages/, so that neither index.html nor attachments/ can be overwritten by a wiki of the same name.
+		pages := map[string]string{}
+
+		for _, wiki := range wikis {
+			pages[wiki.Name] = filepath.Join("pages", wikiRelativePath(wiki.Name, ".html"))
+		}
+
+		for _, wiki := range wikis {
+			// The list entries cached by fetchWikis have no content, so every page is fetched from the server.
+			wiki, err := client.GetWiki(wiki.Id)
+			if err != nil {
+				return err
+			}
+			if err := writeWiki(wiki); err != nil {
+				return err
+			}
+			if err := exportWiki(dir, project, wiki, pages); err != nil {
+				return err
+			}
+
+			fmt.Println("exported", pages[wiki.Name])
+		}
+
+		index := "<ul>\n" + renderWikiTreeHTML(buildWikiTree(wikis), pages) + "</ul>\n"
+		html := fmt.Sprintf(wikiExportTemplate, escapeHTML(project.Name), "index.html", escapeHTML(project.ProjectKey), escapeHTML(project.Name), index)
+
+		return ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0644)
+	},
+}
+
+// exportWiki writes the wiki as HTML with its attachments.
+// The links to other wikis and attachments are rewritten to relative paths.
+func exportWiki(dir string, project backlog.Project, wiki backlog.Wiki, pages map[string]string) error {
+	page := pages[wiki.Name]
+	pageDir := filepath.Dir(page)
+
+	attachments, err := client.GetWikiAttachments(wiki.Id)
+	if err != nil {
+		return err
+	}
+
+	images := map[string]string{}
+
+	for _, attachment := range attachments {
+		name, err := safeFileName(attachment.Name)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join("attachments", fmt.Sprint(wiki.Id), name)
+
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0755)
+
+		if info, err := os.Stat(filepath.Join(dir, path)); err != nil || uint64(info.Size()) != attachment.Size {
+			attachmentId := attachment.Id
+
+			_, err := downloadFile(filepath.Join(dir, path), attachment.Name, func(w io.Writer) error {
+				return client.DownloadWikiAttachment(wiki.Id, attachmentId, w)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		images[attachment.Name] = relativeURL(pageDir, path)
+	}
+
+	resolver := wikiResolver{
+		Page: func(name string) string {
+			if target, ok := pages[name]; ok {
+				return relativeURL(pageDir, target)
+			}
+
+			return "#"
+		},
+		Image: func(src string) string {
+			if path, ok := images[src]; ok {
+				return path
+			}
+
+			return src
+		},
+	}
+
+	var content string
+
+	if project.TextFormattingRule == "markdown" {
+		content = markdownToHTML(wiki.Content, resolver)
+	} else {
+		content = backlogToHTML(wiki.Content, resolver)
+	}
+
+	html := fmt.Sprintf(wikiExportTemplate, escapeHTML(wiki.Name), relativeURL(pageDir, "index.html"), escapeHTML(project.Name), escapeHTML(wiki.Name), content)
+	path := filepath.Join(dir, page)
+
+	os.MkdirAll(filepath.Dir(path), 0755)
+
+	return ioutil.WriteFile(path, []byte(html), 0644)
+}
+
+// relativeURL returns the URL of the target relative to the directory, both of which are relative to the root of exported site.
+func relativeURL(dir, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return target
+	}
+
+	ss := strings.Split(filepath.ToSlash(rel), "/")
+
+	for i, s := range ss {
+		ss[i] = url.PathEscape(s)
+	}
+
+	return strings.Join(ss, "/")
+}
+
+func renderWikiTreeHTML(node *wikiNode, pages map[string]string) (s string) {
+	for _, child := range node.children {
+		if child.wiki == nil {
+			s += "<li>" + escapeHTML(child.name)
+		} else {
+			s += fmt.Sprintf(`<li><a href="%s">%s</a>`, escapeAttribute(relativeURL(".", pages[child.wiki.Name])), escapeHTML(child.name))
+		}
+		if len(child.children) > 0 {
+			s += "\n<ul>\n" + renderWikiTreeHTML(child, pages) + "</ul>\n"
+		}
+
+		s += "</li>\n"
+	}
+
+	return s
+}
+
+func init() {
+	wikiExportCommand.Flags().StringVarP(&wikiExportFormatFlag, "format", "f", "html", "output format (html)")
+	wikiExportCommand.Flags().StringVarP(&wikiExportOutputFlag, "output", "o", ".", "output directory")
+
+	wikiCommand.AddCommand(wikiExportCommand)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+var wikiHistoryCommand = &cobra.Command{
+	Use: "history",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		wikiId, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		if err := fetchWikiHistories(wikiId); err != nil {
+			return err
+		}
+
+		histories, err := readWikiHistories(wikiId)
+		if err != nil {
+			return err
+		}
+
+		for i := len(histories) - 1; i >= 0; i-- {
+			history := histories[i]
+			fmt.Printf("v%d %s by %s\n", history.Version, history.Created.Time().Format("2006-01-02 15:04"), history.CreatedUser.Name)
+		}
+
+		return nil
+	},
+}
+
+var wikiDiffCommand = &cobra.Command{
+	Use: "diff",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		wikiId, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		versions := []uint64{}
+
+		for _, arg := range args[1:] {
+			version, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			versions = append(versions, version)
+		}
+
+		histories, err := readWikiHistories(wikiId)
+		if err != nil || !hasWikiVersions(histories, versions) || len(versions) < 2 {
+			if err := fetchWikiHistories(wikiId); err != nil {
+				return err
+			}
+
+			histories, err = readWikiHistories(wikiId)
+			if err != nil {
+				return err
+			}
+		}
+		if len(histories) == 0 || (len(histories) < 2 && len(versions) == 0) {
+			return fmt.Errorf("wiki %d has no previous version", wikiId)
+		}
+
+		latest := histories[len(histories)-1].Version
+
+		switch len(versions) {
+		case 0:
+			versions = []uint64{histories[len(histories)-2].Version, latest}
+		case 1:
+			versions = append(versions, latest)
+		}
+
+		from, err := findWikiVersion(histories, versions[0])
+		if err != nil {
+			return err
+		}
+
+		to, err := findWikiVersion(histories, versions[1])
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(unifiedDiff(fmt.Sprintf("v%d", from.Version), fmt.Sprintf("v%d", to.Version), from.Content, to.Content))
+
+		return nil
+	},
+}
+
+func hasWikiVersions(histories []backlog.WikiHistory, versions []uint64) bool {
+	for _, version := range versions {
+		if _, err := findWikiVersion(histories, version); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func findWikiVersion(histories []backlog.WikiHistory, version uint64) (history backlog.WikiHistory, err error) {
+	for _, h := range histories {
+		if h.Version == version {
+			return h, nil
+		}
+	}
+
+	return history, fmt.Errorf("version %d not found", version)
+}
+
+// fetchWikiHistories merges the histories of wiki into the cache.
+// Past versions never change, so the cached ones are kept as they are.
+func fetchWikiHistories(wikiId uint64) error {
+	q := url.Values{}
+	q.Add("wikiId", fmt.Sprint(wikiId))
+
+	if time.Now().Sub(lastExecuted(WikiHistoriesCache, q)) < 30*time.Minute {
+		return nil
+	}
+
+	query := url.Values{}
+	query.Add("count", "100")
+
+	histories, err := client.GetWikiHistory(wikiId, query)
+	if err != nil {
+		return err
+	}
+
+	cached, _ := readWikiHistories(wikiId)
+
+	for _, history := range histories {
+		if !hasWikiVersions(cached, []uint64{history.Version}) {
+			cached = append(cached, history)
+		}
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	base, err := cachePath(WikiHistoriesCache)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(base, 0755)
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", wikiId))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	if err := setLastExecuted(WikiHistoriesCache, q); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// readWikiHistories returns the cached histories of wiki sorted by version in ascending order.
+func readWikiHistories(wikiId uint64) (histories []backlog.WikiHistory, err error) {
+	base, err := cachePath(WikiHistoriesCache)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(base, fmt.Sprintf("%d.json", wikiId))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &histories); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].Version < histories[j].Version
+	})
+
+	return histories, nil
+}
+
+func init() {
+	wikiCommand.AddCommand(wikiHistoryCommand)
+	wikiCommand.AddCommand(wikiDiffCommand)
+}
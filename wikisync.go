@@ -0,0 +1,350 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ericaro/frontmatter"
+	backlog "github.com/moutend/go-backlog"
+	"github.com/spf13/cobra"
+)
+
+// wikiBaseDir is the directory in the synchronized directory which holds the content of each wiki as of the last pull or push.
+// It is used as the common ancestor when both the local file and the remote wiki are changed.
+const wikiBaseDir = ".backlog-wiki"
+
+type wikiFile struct {
+	Id      uint64 `fm:"id"`
+	Project string `fm:"project"`
+	Name    string `fm:"name"`
+	Updated string `fm:"updated"`
+	Content string `fm:"content"`
+}
+
+var wikiPullCommand = &cobra.Command{
+	Use: "pull",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		dir := "."
+
+		if len(args) > 1 {
+			dir = args[1]
+		}
+
+		projectKey := args[0]
+
+		if err := fetchProjectByProjectKey(projectKey); err != nil {
+			return err
+		}
+
+		project, err := readProjectByProjectKey(projectKey)
+		if err != nil {
+			return err
+		}
+
+		// Read the server directly instead of the cache, which is throttled and may hold the list entries without the content.
+		query := url.Values{}
+		query.Add("projectIdOrKey", fmt.Sprint(project.Id))
+
+		wikis, err := client.GetWikis(query)
+		if err != nil {
+			return err
+		}
+
+		files, err := readWikiFiles(dir)
+		if err != nil {
+			return err
+		}
+
+		locals := map[uint64]localWikiFile{}
+
+		for _, local := range files {
+			if local.file.Id != 0 {
+				locals[local.file.Id] = local
+			}
+		}
+
+		conflicts := 0
+
+		for _, wiki := range wikis {
+			path := wikiFilePath(dir, wiki.Name)
+			local, ok := locals[wiki.Id]
+
+			if ok {
+				updated, _ := time.Parse(time.RFC3339, local.file.Updated)
+
+				if !wiki.Updated.Time().After(updated) && local.path == path {
+					continue
+				}
+
+				base, _ := readWikiBase(dir, wiki.Id)
+
+				if !sameWikiContent(local.file.Content, base) {
+					fmt.Printf("conflict: %s has local changes\n", local.path)
+					conflicts++
+					continue
+				}
+			}
+			wiki, err = client.GetWiki(wiki.Id)
+			if err != nil {
+				return err
+			}
+			if err := writeWiki(wiki); err != nil {
+				return err
+			}
+			if err := writeWikiFile(dir, project, wiki); err != nil {
+				return err
+			}
+			if ok && local.path != path {
+				if err := os.Remove(local.path); err != nil {
+					return err
+				}
+			}
+
+			fmt.Println("pulled", path)
+		}
+		if conflicts > 0 {
+			return fmt.Errorf("%d conflict(s) found", conflicts)
+		}
+
+		return nil
+	},
+}
+
+var wikiPushForceFlag bool
+var wikiPushCommand = &cobra.Command{
+	Use: "push",
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return nil
+		}
+
+		dir := "."
+
+		if len(args) > 1 {
+			dir = args[1]
+		}
+
+		projectKey := args[0]
+
+		if err := fetchProjectByProjectKey(projectKey); err != nil {
+			return err
+		}
+
+		project, err := readProjectByProjectKey(projectKey)
+		if err != nil {
+			return err
+		}
+
+		files, err := readWikiFiles(dir)
+		if err != nil {
+			return err
+		}
+
+		conflicts := 0
+
+		for _, local := range files {
+			if local.file.Project != "" && local.file.Project != project.ProjectKey {
+				continue
+			}
+			if local.file.Id == 0 {
+				query := url.Values{}
+				query.Add("projectId", fmt.Sprint(project.Id))
+				query.Add("name", local.file.Name)
+				query.Add("content", local.file.Content)
+				query.Add("mailNotify", fmt.Sprint(wikiMailNotifyFlag))
+
+				wiki, err := client.CreateWiki(query)
+				if err != nil {
+					return err
+				}
+				if err := writeWiki(wiki); err != nil {
+					return err
+				}
+				if err := writeWikiFile(dir, project, wiki); err != nil {
+					return err
+				}
+				if path := wikiFilePath(dir, wiki.Name); path != local.path {
+					if err := os.Remove(local.path); err != nil {
+						return err
+					}
+				}
+
+				fmt.Println("created", local.path)
+				continue
+			}
+
+			base, _ := readWikiBase(dir, local.file.Id)
+
+			if sameWikiContent(local.file.Content, base) && wikiFilePath(dir, local.file.Name) == local.path {
+				continue
+			}
+
+			remote, err := client.GetWiki(local.file.Id)
+			if err != nil {
+				return err
+			}
+
+			updated, _ := time.Parse(time.RFC3339, local.file.Updated)
+
+			if remote.Updated.Time().After(updated) && !wikiPushForceFlag {
+				fmt.Printf("conflict: %s was updated at %s by %s\n", local.path, remote.Updated.Time().Format(time.RFC3339), remote.UpdatedUser.Name)
+				fmt.Print(unifiedDiff("base", "local", base, local.file.Content))
+				fmt.Print(unifiedDiff("base", "remote", base, remote.Content))
+				conflicts++
+				continue
+			}
+
+			query := url.Values{}
+			query.Add("name", local.file.Name)
+			query.Add("content", local.file.Content)
+			query.Add("mailNotify", fmt.Sprint(wikiMailNotifyFlag))
+
+			wiki, err := client.UpdateWiki(local.file.Id, query)
+			if err != nil {
+				return err
+			}
+			if err := writeWiki(wiki); err != nil {
+				return err
+			}
+			if err := writeWikiFile(dir, project, wiki); err != nil {
+				return err
+			}
+
+			fmt.Println("pushed", local.path)
+		}
+		if conflicts > 0 {
+			return fmt.Errorf("%d conflict(s) found (use --force to overwrite)", conflicts)
+		}
+
+		return nil
+	},
+}
+
+// wikiFilePath converts the wiki name into the path of Markdown file, using "/" in the name as the directory hierarchy.
+func wikiFilePath(dir, name string) string {
+	return filepath.Join(dir, wikiRelativePath(name, ".md"))
+}
+
+func wikiRelativePath(name, ext string) string {
+	ss := strings.Split(name, "/")
+
+	for i, s := range ss {
+		if s == "" || s == "." || s == ".." {
+			ss[i] = "_"
+		}
+	}
+
+	return filepath.Join(ss...) + ext
+}
+
+type localWikiFile struct {
+	path string
+	file wikiFile
+}
+
+// readWikiFiles reads the Markdown files in the directory.
+// The names of files which have no frontmatter are derived from their paths.
+func readWikiFiles(dir string) (files []localWikiFile, err error) {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == wikiBaseDir {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var file wikiFile
+
+		// Only the files without frontmatter are new pages; a broken one must not be pushed as a duplicate.
+		if strings.HasPrefix(strings.TrimLeft(string(data), "\ufeff"), "---") {
+			if err := frontmatter.Unmarshal(data, &file); err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+		} else {
+			file = wikiFile{Content: string(data)}
+		}
+		if file.Name == "" {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			file.Name = filepath.ToSlash(strings.TrimSuffix(rel, ".md"))
+		}
+
+		files = append(files, localWikiFile{path: path, file: file})
+
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// writeWikiFile writes the wiki into the directory and records its content as the base of the next synchronization.
+func writeWikiFile(dir string, project backlog.Project, wiki backlog.Wiki) error {
+	path := wikiFilePath(dir, wiki.Name)
+
+	s := "---\n"
+	s += fmt.Sprintf("id: %d\n", wiki.Id)
+	s += fmt.Sprintf("project: %s\n", project.ProjectKey)
+	s += fmt.Sprintf("name: %q\n", wiki.Name)
+	s += fmt.Sprintf("updated: %s\n", wiki.Updated.Time().Format(time.RFC3339))
+	s += "---\n"
+	s += wiki.Content
+
+	os.MkdirAll(filepath.Dir(path), 0755)
+
+	if err := ioutil.WriteFile(path, []byte(s), 0644); err != nil {
+		return err
+	}
+
+	base := filepath.Join(dir, wikiBaseDir)
+
+	os.MkdirAll(base, 0755)
+
+	return ioutil.WriteFile(filepath.Join(base, fmt.Sprintf("%d.md", wiki.Id)), []byte(wiki.Content), 0644)
+}
+
+// sameWikiContent compares the content ignoring the leading and trailing white spaces, which may be changed by editors and the frontmatter.
+func sameWikiContent(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+func readWikiBase(dir string, wikiId uint64) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, wikiBaseDir, fmt.Sprintf("%d.md", wikiId)))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func init() {
+	wikiPushCommand.Flags().BoolVarP(&wikiPushForceFlag, "force", "f", false, "overwrite the wiki even if it was updated remotely")
+
+	wikiCommand.AddCommand(wikiPullCommand)
+	wikiCommand.AddCommand(wikiPushCommand)
+}